@@ -52,15 +52,15 @@ var rootCmd = &cobra.Command{
 		c := config.MustGet()
 
 		eventStream := feeder.DialEventStream(c.WebsocketEndpoint, c.GRPCEndpoint, c.EnableTLS, logger)
-		priceProvider := feeder.NewAggregatePriceProvider(c.ExchangesToPairToSymbolMap, c.DataSourceConfigMap, logger)
+		priceProvider := feeder.NewAggregatePriceProvider(c.ExchangesToPairToSymbolMap, c.DataSourceConfigMap, c.Aggregation, logger)
 		kb, valAddr, feederAddr := config.GetAuth(c.FeederMnemonic)
 
 		if c.ValidatorAddr != nil {
 			valAddr = *c.ValidatorAddr
 		}
-		pricePoster := feeder.DialPricePoster(c.GRPCEndpoint, c.ChainID, c.EnableTLS, kb, valAddr, feederAddr, logger)
+		pricePoster := feeder.DialPricePoster(c.GRPCEndpoint, c.ChainID, c.EnableTLS, kb, valAddr, feederAddr, c.ConsensusDeviation, logger)
 
-		f := feeder.NewFeeder(eventStream, priceProvider, pricePoster, logger)
+		f := feeder.NewFeeder(eventStream, priceProvider, pricePoster, c.PriceChange, logger)
 		f.Run()
 		defer f.Close()
 
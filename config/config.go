@@ -5,6 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/NibiruChain/nibiru/v2/x/common/asset"
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -19,6 +22,10 @@ const (
 	defaultWebsocketEndpoint = "ws://localhost:26657/websocket"
 )
 
+// defaultExchangeSymbolsMap maps each source to the pairs it quotes. Symbols are
+// mapped to the pair they are actually quoted in (e.g. BTC_USDT to ubtc:uusdt),
+// and the aggregate price provider routes requested pairs such as ubtc:uusd
+// through the available quotes (ubtc:uusdt * uusdt:uusd).
 var defaultExchangeSymbolsMap = map[string]map[asset.Pair]types.Symbol{
 	// https://api.coingecko.com/api/v3/coins/markets?vs_currency=usd&order=market_cap_desc&per_page=100&page=1
 	// k-yang: default disable Coingecko because they have aggressive rate limiting
@@ -42,33 +49,65 @@ var defaultExchangeSymbolsMap = map[string]map[asset.Pair]types.Symbol{
 
 	// https://api.gateio.ws/api/v4/spot/currency_pairs
 	sources.SourceNameGateIo: {
-		"ubtc:uusd":  "BTC_USDT",
-		"ueth:uusd":  "ETH_USDT",
-		"uusdc:uusd": "USDC_USDT",
-		"uusdt:uusd": "USDT_USD",
-		"uatom:uusd": "ATOM_USDT",
-		"unibi:uusd": "NIBI_USDT",
-		"usol:uusd":  "SOL_USDT",
+		"ubtc:uusdt":  "BTC_USDT",
+		"ueth:uusdt":  "ETH_USDT",
+		"uusdc:uusdt": "USDC_USDT",
+		"uusdt:uusd":  "USDT_USD",
+		"uatom:uusdt": "ATOM_USDT",
+		"unibi:uusdt": "NIBI_USDT",
+		"usol:uusdt":  "SOL_USDT",
+	},
+
+	// https://api.kraken.com/0/public/AssetPairs, keyed by the pair names
+	// returned in responses (e.g. XXBTZUSD) rather than their altname (XBTUSD)
+	sources.SourceNameKraken: {
+		"ubtc:uusd":  "XXBTZUSD",
+		"ueth:uusd":  "XETHZUSD",
+		"uusdt:uusd": "USDTZUSD",
+		"uusdc:uusd": "USDCUSD",
+	},
+
+	// https://api.exchange.coinbase.com/products
+	sources.SourceNameCoinbase: {
+		"ubtc:uusd":  "BTC-USD",
+		"ueth:uusd":  "ETH-USD",
+		"usol:uusd":  "SOL-USD",
+		"uatom:uusd": "ATOM-USD",
 	},
 
 	// https://www.okx.com/api/v5/market/tickers?instType=SPOT
 	sources.SourceNameOkex: {
-		"ubtc:uusd":  "BTC-USDT",
-		"ueth:uusd":  "ETH-USDT",
-		"uusdc:uusd": "USDC-USDT",
-		"uusdt:uusd": "USDT-USDC",
-		"uatom:uusd": "ATOM-USDT",
-		"usol:uusd":  "SOL-USD",
+		"ubtc:uusdt":  "BTC-USDT",
+		"ueth:uusdt":  "ETH-USDT",
+		"uusdc:uusdt": "USDC-USDT",
+		"uusdt:uusdc": "USDT-USDC",
+		"uatom:uusdt": "ATOM-USDT",
+		"usol:uusdt":  "SOL-USDT",
 	},
 
 	// https://api.bybit.com/v5/market/tickers?category=spot
 	sources.SourceNameBybit: {
-		"ubtc:uusd":  "BTCUSDT",
-		"ueth:uusd":  "ETHUSDT",
-		"uusdc:uusd": "USDCUSDT",
-		"uatom:uusd": "ATOMUSDT",
-		"unibi:uusd": "NIBIUSDT",
-		"usol:uusd":  "SOLUSDT",
+		"ubtc:uusdt":  "BTCUSDT",
+		"ueth:uusdt":  "ETHUSDT",
+		"uusdc:uusdt": "USDCUSDT",
+		"uatom:uusdt": "ATOMUSDT",
+		"unibi:uusdt": "NIBIUSDT",
+		"usol:uusdt":  "SOLUSDT",
+	},
+
+	// https://api.kucoin.com/api/v2/symbols
+	sources.SourceNameKucoin: {
+		"unibi:uusdt": "NIBI-USDT",
+	},
+
+	// https://api.mexc.com/api/v3/exchangeInfo
+	sources.SourceNameMexc: {
+		"unibi:uusdt": "NIBIUSDT",
+	},
+
+	// https://api.huobi.pro/v2/settings/common/symbols
+	sources.SourceNameHtx: {
+		"unibi:uusdt": "nibiusdt",
 	},
 
 	sources.SourceNameErisProtocol: {
@@ -76,13 +115,17 @@ var defaultExchangeSymbolsMap = map[string]map[asset.Pair]types.Symbol{
 	},
 
 	sources.SourceNameUniswapV3: {
-		"usda:usd": "USDa:USDT",
+		"usda:uusdt": "USDa:USDT",
 	},
 
 	sources.SourceNameChainLink: {
 		"b2btc:btc": "uBTC/BTC",
 	},
 
+	// sUSDa can instead be read from its ERC-4626 vault with the erc4626
+	// source, once the vault address is configured in DATASOURCE_CONFIG_MAP.
+	// It stays on the Avalon API by default since no verified vault address
+	// is shipped with the feeder.
 	sources.SourceNameAvalon: {
 		"susda:usda": "susda:usda",
 	},
@@ -148,6 +191,156 @@ func Get() (*Config, error) {
 	}
 	conf.DataSourceConfigMap = datasourceConfigMap
 
+	// EVM networks, registered before the data sources referencing them are validated
+	if evmNetworksJson := os.Getenv("EVM_NETWORKS"); evmNetworksJson != "" {
+		err := json.Unmarshal([]byte(evmNetworksJson), &conf.EVMNetworks)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse EVM_NETWORKS: %w", err)
+		}
+		if err := types.RegisterNetworks(conf.EVMNetworks); err != nil {
+			return nil, fmt.Errorf("invalid EVM_NETWORKS: %w", err)
+		}
+	}
+
+	// price aggregation
+	conf.Aggregation = types.DefaultAggregationConfig()
+	if strategy := os.Getenv("AGGREGATION_STRATEGY"); strategy != "" {
+		conf.Aggregation.Strategy = types.AggregationStrategy(strategy)
+	}
+	if maxStdDev := os.Getenv("OUTLIER_MAX_STDDEV"); maxStdDev != "" {
+		v, err := strconv.ParseFloat(maxStdDev, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse OUTLIER_MAX_STDDEV: %w", err)
+		}
+		conf.Aggregation.OutlierMaxStdDev = v
+	}
+	if maxDeviationPct := os.Getenv("OUTLIER_MAX_DEVIATION_PCT"); maxDeviationPct != "" {
+		v, err := strconv.ParseFloat(maxDeviationPct, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse OUTLIER_MAX_DEVIATION_PCT: %w", err)
+		}
+		conf.Aggregation.OutlierMaxDeviationPct = v
+	}
+	if twapWindow := os.Getenv("TWAP_WINDOW"); twapWindow != "" {
+		v, err := time.ParseDuration(twapWindow)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse TWAP_WINDOW: %w", err)
+		}
+		conf.Aggregation.TWAPWindow = v
+	}
+	if stablecoins := os.Getenv("STABLECOINS"); stablecoins != "" {
+		conf.Aggregation.Stablecoins = strings.Split(stablecoins, ",")
+		for i, stablecoin := range conf.Aggregation.Stablecoins {
+			conf.Aggregation.Stablecoins[i] = strings.TrimSpace(stablecoin)
+		}
+	}
+	if depegThresholdPct := os.Getenv("STABLECOIN_DEPEG_THRESHOLD_PCT"); depegThresholdPct != "" {
+		v, err := strconv.ParseFloat(depegThresholdPct, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse STABLECOIN_DEPEG_THRESHOLD_PCT: %w", err)
+		}
+		conf.Aggregation.StablecoinDepegThresholdPct = v
+	}
+	if minSourcesMapJson := os.Getenv("MIN_SOURCES_MAP"); minSourcesMapJson != "" {
+		minSourcesMap := map[string]int{}
+		err := json.Unmarshal([]byte(minSourcesMapJson), &minSourcesMap)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse MIN_SOURCES_MAP: %w", err)
+		}
+		conf.Aggregation.MinSources = make(map[asset.Pair]int, len(minSourcesMap))
+		for pair, minSources := range minSourcesMap {
+			conf.Aggregation.MinSources[asset.MustNewPair(pair)] = minSources
+		}
+	}
+	if derivedPairsMapJson := os.Getenv("DERIVED_PAIRS_MAP"); derivedPairsMapJson != "" {
+		derivedPairsMap := map[string]types.DerivedPair{}
+		err := json.Unmarshal([]byte(derivedPairsMapJson), &derivedPairsMap)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse DERIVED_PAIRS_MAP: %w", err)
+		}
+		for pair, derivedPair := range derivedPairsMap {
+			conf.Aggregation.DerivedPairs[asset.MustNewPair(pair)] = derivedPair
+		}
+	}
+	if sourceTiersMapJson := os.Getenv("SOURCE_TIERS_MAP"); sourceTiersMapJson != "" {
+		sourceTiersMap := map[string][][]string{}
+		err := json.Unmarshal([]byte(sourceTiersMapJson), &sourceTiersMap)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse SOURCE_TIERS_MAP: %w", err)
+		}
+		conf.Aggregation.SourceTiers = make(map[asset.Pair][][]string, len(sourceTiersMap))
+		for pair, tiers := range sourceTiersMap {
+			conf.Aggregation.SourceTiers[asset.MustNewPair(pair)] = tiers
+		}
+	}
+	if priceBoundsMapJson := os.Getenv("PRICE_BOUNDS_MAP"); priceBoundsMapJson != "" {
+		priceBoundsMap := map[string]types.PriceBound{}
+		err := json.Unmarshal([]byte(priceBoundsMapJson), &priceBoundsMap)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse PRICE_BOUNDS_MAP: %w", err)
+		}
+		for pair, bound := range priceBoundsMap {
+			conf.Aggregation.PriceBounds[asset.MustNewPair(pair)] = bound
+		}
+	}
+	if maxFailures := os.Getenv("CIRCUIT_BREAKER_MAX_FAILURES"); maxFailures != "" {
+		v, err := strconv.Atoi(maxFailures)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CIRCUIT_BREAKER_MAX_FAILURES: %w", err)
+		}
+		conf.Aggregation.CircuitBreaker.MaxFailures = v
+	}
+	if maxOutliers := os.Getenv("CIRCUIT_BREAKER_MAX_OUTLIERS"); maxOutliers != "" {
+		v, err := strconv.Atoi(maxOutliers)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CIRCUIT_BREAKER_MAX_OUTLIERS: %w", err)
+		}
+		conf.Aggregation.CircuitBreaker.MaxOutliers = v
+	}
+	if cooldown := os.Getenv("CIRCUIT_BREAKER_COOLDOWN"); cooldown != "" {
+		v, err := time.ParseDuration(cooldown)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CIRCUIT_BREAKER_COOLDOWN: %w", err)
+		}
+		conf.Aggregation.CircuitBreaker.Cooldown = v
+	}
+
+	// price change guard
+	conf.PriceChange = types.DefaultPriceChangeConfig()
+	if maxChangePct := os.Getenv("MAX_PRICE_CHANGE_PCT"); maxChangePct != "" {
+		v, err := strconv.ParseFloat(maxChangePct, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse MAX_PRICE_CHANGE_PCT: %w", err)
+		}
+		conf.PriceChange.MaxChangePct = v
+	}
+	if action := os.Getenv("PRICE_CHANGE_ACTION"); action != "" {
+		conf.PriceChange.Action = types.PriceChangeAction(action)
+	}
+	if settlePeriods := os.Getenv("PRICE_CHANGE_SETTLE_PERIODS"); settlePeriods != "" {
+		v, err := strconv.Atoi(settlePeriods)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse PRICE_CHANGE_SETTLE_PERIODS: %w", err)
+		}
+		conf.PriceChange.SettlePeriods = v
+	}
+
+	// consensus deviation check
+	if maxDeviationPct := os.Getenv("MAX_CONSENSUS_DEVIATION_PCT"); maxDeviationPct != "" {
+		v, err := strconv.ParseFloat(maxDeviationPct, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse MAX_CONSENSUS_DEVIATION_PCT: %w", err)
+		}
+		conf.ConsensusDeviation.MaxDeviationPct = v
+	}
+	if abstain := os.Getenv("CONSENSUS_DEVIATION_ABSTAIN"); abstain != "" {
+		v, err := strconv.ParseBool(abstain)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CONSENSUS_DEVIATION_ABSTAIN: %w", err)
+		}
+		conf.ConsensusDeviation.Abstain = v
+	}
+
 	// optional validator address (for delegated feeders)
 	valAddrStr := os.Getenv("VALIDATOR_ADDRESS")
 	if valAddrStr != "" {
@@ -163,6 +356,10 @@ func Get() (*Config, error) {
 type Config struct {
 	ExchangesToPairToSymbolMap map[string]map[asset.Pair]types.Symbol
 	DataSourceConfigMap        map[string]json.RawMessage
+	EVMNetworks                map[string]types.NetworkConfig
+	Aggregation                types.AggregationConfig
+	PriceChange                types.PriceChangeConfig
+	ConsensusDeviation         types.ConsensusDeviationConfig
 	GRPCEndpoint               string
 	WebsocketEndpoint          string
 	FeederMnemonic             string
@@ -184,5 +381,64 @@ func (c *Config) Validate() error {
 	if c.GRPCEndpoint == "" {
 		return fmt.Errorf("no grpc endpoint")
 	}
+	if err := c.Aggregation.Validate(); err != nil {
+		return fmt.Errorf("invalid aggregation config: %w", err)
+	}
+	if maxWindow := types.PriceHistorySize * sources.UpdateTick; c.Aggregation.TWAPWindow > maxWindow {
+		return fmt.Errorf("TWAP window %s is longer than the %s of price history kept per source", c.Aggregation.TWAPWindow, maxWindow)
+	}
+	for pair, tiers := range c.Aggregation.SourceTiers {
+		for _, tier := range tiers {
+			for _, sourceName := range tier {
+				if _, ok := c.ExchangesToPairToSymbolMap[sourceName]; !ok {
+					return fmt.Errorf("invalid source tiers for %s: source %s is not configured", pair, sourceName)
+				}
+			}
+		}
+	}
+	if err := c.PriceChange.Validate(); err != nil {
+		return fmt.Errorf("invalid price change config: %w", err)
+	}
+	if err := c.ConsensusDeviation.Validate(); err != nil {
+		return fmt.Errorf("invalid consensus deviation config: %w", err)
+	}
+	for sourceName, sourceConfig := range c.DataSourceConfigMap {
+		if _, err := types.ParseStalenessConfig(sourceConfig); err != nil {
+			return fmt.Errorf("invalid staleness config for %s: %w", sourceName, err)
+		}
+	}
+	for sourceName, sourceConfig := range c.DataSourceConfigMap {
+		if !sources.IsGenericHTTPSource(sourceName) {
+			continue
+		}
+		if err := sources.ValidateGenericHTTPConfig(sourceConfig); err != nil {
+			return fmt.Errorf("%s: %w", sourceName, err)
+		}
+	}
+	if sourceConfig, ok := c.DataSourceConfigMap[sources.SourceNameCosmWasmQuery]; ok {
+		if _, err := sources.ParseCosmWasmQueryConfig(sourceConfig); err != nil {
+			return err
+		}
+	}
+	if sourceConfig, ok := c.DataSourceConfigMap[sources.SourceNameEVMCall]; ok {
+		if _, err := sources.ParseEVMCallConfig(sourceConfig); err != nil {
+			return err
+		}
+	}
+	if sourceConfig, ok := c.DataSourceConfigMap[sources.SourceNameERC4626]; ok {
+		if _, err := sources.ParseERC4626Config(sourceConfig); err != nil {
+			return err
+		}
+	}
+	if sourceConfig, ok := c.DataSourceConfigMap[sources.SourceNamePyth]; ok {
+		if _, err := sources.ParsePythConfig(sourceConfig); err != nil {
+			return err
+		}
+	}
+	if sourceConfig, ok := c.DataSourceConfigMap[sources.SourceNameChainLink]; ok {
+		if _, err := sources.ParseChainlinkConfig(sourceConfig); err != nil {
+			return err
+		}
+	}
 	return nil
 }
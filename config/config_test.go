@@ -3,9 +3,13 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/NibiruChain/nibiru/v2/gosdk"
+	"github.com/NibiruChain/nibiru/v2/x/common/asset"
 	"github.com/stretchr/testify/require"
+
+	"github.com/NibiruChain/pricefeeder/types"
 )
 
 func init() {
@@ -46,3 +50,260 @@ func TestConfig_Without_EXCHANGE_SYMBOLS_MAP(t *testing.T) {
 	_, err := Get()
 	require.NoError(t, err)
 }
+
+func TestConfig_MIN_SOURCES_MAP(t *testing.T) {
+	t.Setenv("CHAIN_ID", "nibiru-localnet-0")
+	t.Setenv("FEEDER_MNEMONIC", "earth wash broom grow recall fitness")
+
+	t.Setenv("MIN_SOURCES_MAP", `{"ubtc:uusd": 3, "ustnibi:unibi": 1}`)
+	conf, err := Get()
+	require.NoError(t, err)
+	require.Equal(t, 3, conf.Aggregation.MinSourcesFor("ubtc:uusd"))
+	require.Equal(t, 1, conf.Aggregation.MinSourcesFor("ustnibi:unibi"))
+	require.Equal(t, 1, conf.Aggregation.MinSourcesFor("ueth:uusd"))
+
+	t.Setenv("MIN_SOURCES_MAP", `{"ubtc:uusd": 0}`)
+	_, err = Get()
+	require.ErrorContains(t, err, "min sources")
+}
+
+func TestConfig_DERIVED_PAIRS_MAP(t *testing.T) {
+	t.Setenv("CHAIN_ID", "nibiru-localnet-0")
+	t.Setenv("FEEDER_MNEMONIC", "earth wash broom grow recall fitness")
+
+	t.Setenv("DERIVED_PAIRS_MAP", `{"ueth:ubtc": {"operation": "divide", "pairs": ["ueth:uusd", "ubtc:uusd"]}}`)
+	conf, err := Get()
+	require.NoError(t, err)
+	require.Contains(t, conf.Aggregation.DerivedPairs, asset.Pair("ueth:ubtc"))
+	require.Contains(t, conf.Aggregation.DerivedPairs, asset.Pair("ustnibi:uusd"), "default derived pairs are kept")
+
+	t.Setenv("DERIVED_PAIRS_MAP", `{"ueth:uusd": {"operation": "divide", "pairs": ["ueth:ubtc", "ubtc:uusd"]}, "ueth:ubtc": {"operation": "divide", "pairs": ["ueth:uusd", "ubtc:uusd"]}}`)
+	_, err = Get()
+	require.ErrorContains(t, err, "cycle detected")
+}
+
+func TestConfig_DATASOURCE_CONFIG_MAP_staleness(t *testing.T) {
+	t.Setenv("CHAIN_ID", "nibiru-localnet-0")
+	t.Setenv("FEEDER_MNEMONIC", "earth wash broom grow recall fitness")
+
+	t.Setenv("DATASOURCE_CONFIG_MAP", `{"chainlink": {"max_price_age": "2m", "pair_max_price_age": {"ubtc:uusd": "1m"}}}`)
+	_, err := Get()
+	require.NoError(t, err)
+
+	t.Setenv("DATASOURCE_CONFIG_MAP", `{"chainlink": {"max_price_age": "two minutes"}}`)
+	_, err = Get()
+	require.ErrorContains(t, err, "invalid staleness config for chainlink")
+}
+
+func TestConfig_PRICE_BOUNDS_MAP(t *testing.T) {
+	t.Setenv("CHAIN_ID", "nibiru-localnet-0")
+	t.Setenv("FEEDER_MNEMONIC", "earth wash broom grow recall fitness")
+
+	conf, err := Get()
+	require.NoError(t, err)
+	require.Equal(t, types.DefaultPriceBounds(), conf.Aggregation.PriceBounds)
+
+	t.Setenv("PRICE_BOUNDS_MAP", `{"ubtc:uusd": {"min": 1000, "max": 1000000, "action": "abstain"}}`)
+	conf, err = Get()
+	require.NoError(t, err)
+	require.Equal(t, types.PriceBound{Min: 1000, Max: 1000000, Action: types.PriceBoundAbstain}, conf.Aggregation.PriceBounds["ubtc:uusd"])
+	require.Contains(t, conf.Aggregation.PriceBounds, asset.Pair("usda:uusdt"), "default price bounds are kept")
+
+	t.Setenv("PRICE_BOUNDS_MAP", `{"ubtc:uusd": {"min": 1000, "action": "ignore"}}`)
+	_, err = Get()
+	require.ErrorContains(t, err, "unknown price bound action")
+}
+
+func TestConfig_MAX_PRICE_CHANGE_PCT(t *testing.T) {
+	t.Setenv("CHAIN_ID", "nibiru-localnet-0")
+	t.Setenv("FEEDER_MNEMONIC", "earth wash broom grow recall fitness")
+
+	t.Setenv("MAX_PRICE_CHANGE_PCT", "15")
+	t.Setenv("PRICE_CHANGE_ACTION", "confirm")
+	conf, err := Get()
+	require.NoError(t, err)
+	require.Equal(t, types.PriceChangeConfig{MaxChangePct: 15, Action: types.PriceChangeConfirm, SettlePeriods: 3}, conf.PriceChange)
+
+	t.Setenv("PRICE_CHANGE_SETTLE_PERIODS", "5")
+	conf, err = Get()
+	require.NoError(t, err)
+	require.Equal(t, 5, conf.PriceChange.SettlePeriods)
+
+	t.Setenv("PRICE_CHANGE_SETTLE_PERIODS", "0")
+	_, err = Get()
+	require.ErrorContains(t, err, "price change settle periods must be at least 1")
+	t.Setenv("PRICE_CHANGE_SETTLE_PERIODS", "")
+
+	t.Setenv("PRICE_CHANGE_ACTION", "clamp")
+	_, err = Get()
+	require.ErrorContains(t, err, "unknown price change action")
+}
+
+func TestConfig_TWAP_WINDOW(t *testing.T) {
+	t.Setenv("CHAIN_ID", "nibiru-localnet-0")
+	t.Setenv("FEEDER_MNEMONIC", "earth wash broom grow recall fitness")
+
+	t.Setenv("TWAP_WINDOW", "30m")
+	conf, err := Get()
+	require.NoError(t, err)
+	require.Equal(t, 30*time.Minute, conf.Aggregation.TWAPWindow)
+
+	t.Setenv("TWAP_WINDOW", "1h")
+	_, err = Get()
+	require.ErrorContains(t, err, "TWAP window 1h0m0s is longer than the 34m8s of price history kept per source")
+}
+
+func TestConfig_SOURCE_TIERS_MAP(t *testing.T) {
+	t.Setenv("CHAIN_ID", "nibiru-localnet-0")
+	t.Setenv("FEEDER_MNEMONIC", "earth wash broom grow recall fitness")
+
+	t.Setenv("SOURCE_TIERS_MAP", `{"ubtc:uusd": [["coinbase", "kraken", "bitfinex"], ["okex", "bybit"], ["gateio"]]}`)
+	conf, err := Get()
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"coinbase", "kraken", "bitfinex"}, {"okex", "bybit"}, {"gateio"}}, conf.Aggregation.SourceTiers["ubtc:uusd"])
+
+	t.Setenv("SOURCE_TIERS_MAP", `{"ubtc:uusd": [["binance", "okex"], ["okex"]]}`)
+	_, err = Get()
+	require.ErrorContains(t, err, "source okex is listed more than once")
+
+	t.Setenv("SOURCE_TIERS_MAP", `{"ubtc:uusd": [["binance"], []]}`)
+	_, err = Get()
+	require.ErrorContains(t, err, "source tier 2 is empty")
+
+	t.Setenv("SOURCE_TIERS_MAP", `{"ubtc:uusd": [["okex"], ["binance"]]}`)
+	_, err = Get()
+	require.ErrorContains(t, err, "source binance is not configured")
+}
+
+func TestConfig_CIRCUIT_BREAKER(t *testing.T) {
+	t.Setenv("CHAIN_ID", "nibiru-localnet-0")
+	t.Setenv("FEEDER_MNEMONIC", "earth wash broom grow recall fitness")
+
+	conf, err := Get()
+	require.NoError(t, err)
+	require.False(t, conf.Aggregation.CircuitBreaker.Enabled())
+
+	t.Setenv("CIRCUIT_BREAKER_MAX_FAILURES", "5")
+	t.Setenv("CIRCUIT_BREAKER_MAX_OUTLIERS", "3")
+	t.Setenv("CIRCUIT_BREAKER_COOLDOWN", "2m")
+	conf, err = Get()
+	require.NoError(t, err)
+	require.Equal(t, types.CircuitBreakerConfig{MaxFailures: 5, MaxOutliers: 3, Cooldown: 2 * time.Minute}, conf.Aggregation.CircuitBreaker)
+
+	t.Setenv("CIRCUIT_BREAKER_COOLDOWN", "0s")
+	_, err = Get()
+	require.ErrorContains(t, err, "circuit breaker cooldown must be positive")
+}
+
+func TestConfig_DATASOURCE_CONFIG_MAP_generic_http(t *testing.T) {
+	t.Setenv("CHAIN_ID", "nibiru-localnet-0")
+	t.Setenv("FEEDER_MNEMONIC", "earth wash broom grow recall fitness")
+
+	t.Setenv("DATASOURCE_CONFIG_MAP", `{"generic_http": {"url": "https://api.example.com/tickers", "tickers_path": "$.data", "symbol_path": "symbol", "price_path": "last"}}`)
+	_, err := Get()
+	require.NoError(t, err)
+
+	t.Setenv("DATASOURCE_CONFIG_MAP", `{"generic_http": {"url": "https://api.example.com/tickers"}}`)
+	_, err = Get()
+	require.ErrorContains(t, err, "invalid generic_http config: no price_path")
+
+	t.Setenv("DATASOURCE_CONFIG_MAP", `{"generic_http:kraken": {"url": "https://api.example.com/tickers"}}`)
+	_, err = Get()
+	require.ErrorContains(t, err, "generic_http:kraken: invalid generic_http config: no price_path")
+}
+
+func TestConfig_DATASOURCE_CONFIG_MAP_pyth(t *testing.T) {
+	t.Setenv("CHAIN_ID", "nibiru-localnet-0")
+	t.Setenv("FEEDER_MNEMONIC", "earth wash broom grow recall fitness")
+
+	t.Setenv("DATASOURCE_CONFIG_MAP", `{"pyth": {"endpoint": "https://hermes.pyth.network", "max_conf_ratio": 0.02}}`)
+	_, err := Get()
+	require.NoError(t, err)
+
+	t.Setenv("DATASOURCE_CONFIG_MAP", `{"pyth": {"max_conf_ratio": "1%"}}`)
+	_, err = Get()
+	require.ErrorContains(t, err, "invalid pyth config")
+
+	t.Setenv("DATASOURCE_CONFIG_MAP", `{"pyth": {"max_conf_ratio": -0.01}}`)
+	_, err = Get()
+	require.ErrorContains(t, err, "invalid pyth config: max_conf_ratio must not be negative")
+
+	t.Setenv("DATASOURCE_CONFIG_MAP", `{"pyth": {"endpoint": "hermes.pyth.network"}}`)
+	_, err = Get()
+	require.ErrorContains(t, err, "invalid pyth config: invalid endpoint")
+}
+
+func TestConfig_DATASOURCE_CONFIG_MAP_cosmwasm_query(t *testing.T) {
+	t.Setenv("CHAIN_ID", "nibiru-localnet-0")
+	t.Setenv("FEEDER_MNEMONIC", "earth wash broom grow recall fitness")
+
+	t.Setenv("DATASOURCE_CONFIG_MAP", `{"cosmwasm_query": {"queries": {"stnibi": {"contract": "nibi1eris", "query": {"state": {}}, "result_path": "$.exchange_rate"}}}}`)
+	_, err := Get()
+	require.NoError(t, err)
+
+	t.Setenv("DATASOURCE_CONFIG_MAP", `{"cosmwasm_query": {"queries": {"stnibi": {"query": {"state": {}}}}}}`)
+	_, err = Get()
+	require.ErrorContains(t, err, "invalid cosmwasm_query config for stnibi: no contract")
+}
+
+func TestConfig_DATASOURCE_CONFIG_MAP_evm_call(t *testing.T) {
+	t.Setenv("CHAIN_ID", "nibiru-localnet-0")
+	t.Setenv("FEEDER_MNEMONIC", "earth wash broom grow recall fitness")
+
+	t.Setenv("DATASOURCE_CONFIG_MAP", `{"evm_call": {"calls": {"wsteth": {"network": "ethereum", "contract": "0x7f39C581F595B53c5cb19bD0b3f8dA6c935E2Ca0", "method": "stEthPerToken()(uint256)", "decimals": 18}}}}`)
+	_, err := Get()
+	require.NoError(t, err)
+
+	t.Setenv("DATASOURCE_CONFIG_MAP", `{"evm_call": {"calls": {"wsteth": {"network": "unknown", "contract": "0x7f39C581F595B53c5cb19bD0b3f8dA6c935E2Ca0", "method": "stEthPerToken()"}}}}`)
+	_, err = Get()
+	require.ErrorContains(t, err, "invalid evm_call config for wsteth: unsupported network: unknown")
+}
+
+func TestConfig_DATASOURCE_CONFIG_MAP_erc4626(t *testing.T) {
+	t.Setenv("CHAIN_ID", "nibiru-localnet-0")
+	t.Setenv("FEEDER_MNEMONIC", "earth wash broom grow recall fitness")
+
+	t.Setenv("DATASOURCE_CONFIG_MAP", `{"erc4626": {"vaults": {"sdai": {"network": "ethereum", "vault": "0x83F20F44975D03b1b09e64809B757c47f942BEeA"}}}}`)
+	_, err := Get()
+	require.NoError(t, err)
+
+	t.Setenv("DATASOURCE_CONFIG_MAP", `{"erc4626": {"vaults": {"sdai": {"network": "ethereum", "vault": "sdai"}}}}`)
+	_, err = Get()
+	require.ErrorContains(t, err, "invalid erc4626 config for sdai: invalid vault address")
+}
+
+func TestConfig_DATASOURCE_CONFIG_MAP_chainlink(t *testing.T) {
+	t.Setenv("CHAIN_ID", "nibiru-localnet-0")
+	t.Setenv("FEEDER_MNEMONIC", "earth wash broom grow recall fitness")
+
+	t.Setenv("DATASOURCE_CONFIG_MAP", `{"chainlink": {"feeds": {"ETH/USD": {"network": "ethereum", "address": "0x5f4eC3Df9cbd43714FE2740f5E3616155c5b8419", "description": "ETH / USD", "max_data_age": "1h"}}}}`)
+	_, err := Get()
+	require.NoError(t, err)
+
+	t.Setenv("DATASOURCE_CONFIG_MAP", `{"chainlink": {"feeds": {"ETH/USD": {"network": "unknown", "address": "0x5f4eC3Df9cbd43714FE2740f5E3616155c5b8419"}}}}`)
+	_, err = Get()
+	require.ErrorContains(t, err, "invalid chainlink config for ETH/USD: unsupported network: unknown")
+}
+
+func TestConfig_EVM_NETWORKS(t *testing.T) {
+	t.Setenv("CHAIN_ID", "nibiru-localnet-0")
+	t.Setenv("FEEDER_MNEMONIC", "earth wash broom grow recall fitness")
+
+	t.Setenv("EVM_NETWORKS", `{"arbitrum": {"chain_id": 42161, "rpc_endpoints": ["https://arb1.arbitrum.io/rpc", "https://arbitrum-one.publicnode.com"], "timeout": "5s"}}`)
+	t.Setenv("DATASOURCE_CONFIG_MAP", `{"chainlink": {"feeds": {"ETH/USD": {"network": "arbitrum", "address": "0x639Fe6ab55C921f74e7fac1ee960C0B6293ba612"}}}}`)
+	conf, err := Get()
+	require.NoError(t, err)
+	require.Equal(t, types.NetworkConfig{
+		ChainID:          42161,
+		DefaultEndpoints: []string{"https://arb1.arbitrum.io/rpc", "https://arbitrum-one.publicnode.com"},
+		Timeout:          types.Duration(5 * time.Second),
+	}, conf.EVMNetworks["arbitrum"])
+
+	t.Setenv("EVM_NETWORKS", `{"base": {"chain_id": 8453}}`)
+	_, err = Get()
+	require.ErrorContains(t, err, "invalid EVM_NETWORKS: invalid network base: no rpc endpoints")
+
+	t.Setenv("EVM_NETWORKS", `{"base": {"chain_id": "base"}}`)
+	_, err = Get()
+	require.ErrorContains(t, err, "failed to parse EVM_NETWORKS")
+}
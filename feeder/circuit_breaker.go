@@ -0,0 +1,235 @@
+package feeder
+
+import (
+	"sync"
+	"time"
+
+	"github.com/NibiruChain/nibiru/v2/x/common/asset"
+	"github.com/NibiruChain/nibiru/v2/x/common/set"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rs/zerolog"
+
+	"github.com/NibiruChain/pricefeeder/metrics"
+	"github.com/NibiruChain/pricefeeder/types"
+)
+
+// circuitState is the state of a circuit breaker, exported as the value of
+// the source_circuit_breaker_state gauge.
+type circuitState int
+
+const (
+	// circuitClosed lets the prices of the source through.
+	circuitClosed circuitState = iota
+	// circuitOpen excludes the source until the cooldown is over.
+	circuitOpen
+	// circuitHalfOpen lets the prices of the source through to probe it. The
+	// next price decides whether the breaker closes or opens again.
+	circuitHalfOpen
+)
+
+func (s circuitState) String() string {
+	switch s {
+	case circuitOpen:
+		return "open"
+	case circuitHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+var circuitBreakerState = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: metrics.PrometheusNamespace,
+	Name:      "source_circuit_breaker_state",
+	Help:      "The state of the circuit breaker of each source: 0 closed, 1 open, 2 half-open",
+}, []string{"source"})
+
+var (
+	_ types.PriceProvider = (*circuitBreakerPriceProvider)(nil)
+	_ fetchTracker        = (*PriceProvider)(nil)
+)
+
+// fetchTracker is implemented by the PriceProvider of a source, which tracks
+// the fetches of the source.
+type fetchTracker interface {
+	// fetchStatus returns the number of fetches of the source so far, and the
+	// number of consecutive fetches that failed as a whole.
+	fetchStatus() (fetches uint64, failures int)
+}
+
+// circuitBreakerPriceProvider wraps the PriceProvider of a source and stops
+// returning its prices after too many consecutive failed fetches of the
+// source, or outlier rejections of one of its pairs. A symbol missing from
+// otherwise successful fetches only invalidates its own pair. After a
+// cooldown, the source is probed again.
+type circuitBreakerPriceProvider struct {
+	types.PriceProvider
+	sourceName string
+	// pairs are the pairs configured for the source. Invalid prices of other
+	// pairs are expected and do not count as failures.
+	pairs  set.Set[asset.Pair]
+	config types.CircuitBreakerConfig
+	logger zerolog.Logger
+	now    func() time.Time
+	// fetches reports the fetch failures of the source, or is nil when the
+	// wrapped PriceProvider does not track its fetches.
+	fetches fetchTracker
+
+	mu       sync.Mutex
+	state    circuitState
+	outliers map[asset.Pair]int
+	// countedFetch holds, for each pair, the fetch whose outlier rejection
+	// was last counted. Routing evaluates the same leg for every pair quoted
+	// through it, and each fetch must count once.
+	countedFetch map[asset.Pair]uint64
+	openedAt     time.Time
+}
+
+func newCircuitBreakerPriceProvider(
+	pp types.PriceProvider,
+	sourceName string,
+	pairs set.Set[asset.Pair],
+	config types.CircuitBreakerConfig,
+	logger zerolog.Logger,
+) *circuitBreakerPriceProvider {
+	circuitBreakerState.WithLabelValues(sourceName).Set(float64(circuitClosed))
+	fetches, _ := pp.(fetchTracker)
+	return &circuitBreakerPriceProvider{
+		PriceProvider: pp,
+		sourceName:    sourceName,
+		pairs:         pairs,
+		config:        config,
+		logger:        logger.With().Str("component", "circuit-breaker").Str("source", sourceName).Logger(),
+		now:           time.Now,
+		fetches:       fetches,
+		outliers:      map[asset.Pair]int{},
+		countedFetch:  map[asset.Pair]uint64{},
+	}
+}
+
+// GetPrice returns the price of the wrapped PriceProvider, or an invalid
+// price while the breaker is open.
+func (c *circuitBreakerPriceProvider) GetPrice(pair asset.Pair) types.Price {
+	if !c.pairs.Has(pair) {
+		return c.PriceProvider.GetPrice(pair)
+	}
+	if !c.allow() {
+		return types.Price{
+			Pair:       pair,
+			Price:      types.PriceAbstain,
+			SourceName: c.sourceName,
+			Valid:      false,
+		}
+	}
+
+	price := c.PriceProvider.GetPrice(pair)
+	var failures int
+	if c.fetches != nil {
+		_, failures = c.fetches.fetchStatus()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.config.MaxFailures > 0 && failures >= c.config.MaxFailures {
+		c.open(pair, "too many fetch failures", failures)
+		return types.Price{
+			Pair:       pair,
+			Price:      types.PriceAbstain,
+			SourceName: c.sourceName,
+			Valid:      false,
+		}
+	}
+	if c.state == circuitHalfOpen {
+		if failures > 0 {
+			c.open(pair, "failed fetch while half-open", failures)
+			return types.Price{
+				Pair:       pair,
+				Price:      types.PriceAbstain,
+				SourceName: c.sourceName,
+				Valid:      false,
+			}
+		} else if price.Valid && c.config.MaxOutliers == 0 {
+			c.setState(circuitClosed)
+		}
+	}
+	return price
+}
+
+// recordOutlier counts a price of the pair rejected as an outlier, once per
+// fetch of the source.
+func (c *circuitBreakerPriceProvider) recordOutlier(pair asset.Pair) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.newFetch(pair) {
+		return
+	}
+	c.outliers[pair]++
+	if c.state == circuitHalfOpen || (c.config.MaxOutliers > 0 && c.outliers[pair] >= c.config.MaxOutliers) {
+		c.open(pair, "too many outlier prices", c.outliers[pair])
+	}
+}
+
+// recordAccepted records a price of the pair that passed outlier rejection.
+func (c *circuitBreakerPriceProvider) recordAccepted(pair asset.Pair) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.newFetch(pair) {
+		return
+	}
+	delete(c.outliers, pair)
+	if c.state == circuitHalfOpen {
+		c.setState(circuitClosed)
+	}
+}
+
+// newFetch reports whether the price of the pair comes from a fetch of the
+// source whose outlier rejection is not counted yet. Without fetch tracking,
+// every rejection counts. Must be called with the lock held.
+func (c *circuitBreakerPriceProvider) newFetch(pair asset.Pair) bool {
+	if c.fetches == nil {
+		return true
+	}
+	fetch, _ := c.fetches.fetchStatus()
+	if counted, ok := c.countedFetch[pair]; ok && counted == fetch {
+		return false
+	}
+	c.countedFetch[pair] = fetch
+	return true
+}
+
+// allow reports whether the prices of the source can be used, moving the
+// breaker to half-open once the cooldown is over.
+func (c *circuitBreakerPriceProvider) allow() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.state != circuitOpen {
+		return true
+	}
+	if c.now().Sub(c.openedAt) < c.config.Cooldown {
+		return false
+	}
+	c.setState(circuitHalfOpen)
+	return true
+}
+
+// open must be called with the lock held.
+func (c *circuitBreakerPriceProvider) open(pair asset.Pair, reason string, count int) {
+	c.logger.Warn().
+		Str("pair", pair.String()).
+		Str("reason", reason).
+		Int("count", count).
+		Dur("cooldown", c.config.Cooldown).
+		Msg("opening circuit breaker, source excluded from aggregation")
+	c.outliers = map[asset.Pair]int{}
+	c.openedAt = c.now()
+	c.setState(circuitOpen)
+}
+
+// setState must be called with the lock held.
+func (c *circuitBreakerPriceProvider) setState(state circuitState) {
+	if state != c.state {
+		c.logger.Info().Str("from", c.state.String()).Str("to", state.String()).Msg("circuit breaker state changed")
+	}
+	c.state = state
+	circuitBreakerState.WithLabelValues(c.sourceName).Set(float64(state))
+}
@@ -0,0 +1,157 @@
+package feeder
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/NibiruChain/nibiru/v2/x/common/asset"
+	"github.com/NibiruChain/nibiru/v2/x/common/denoms"
+	"github.com/NibiruChain/nibiru/v2/x/common/set"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NibiruChain/pricefeeder/types"
+)
+
+func TestCircuitBreakerPriceProvider(t *testing.T) {
+	pair := asset.Registry.Pair(denoms.BTC, denoms.USD)
+	other := asset.Registry.Pair(denoms.ETH, denoms.USD)
+	now := time.Now()
+	newBreaker := func(p *testPriceProvider) *circuitBreakerPriceProvider {
+		cb := newCircuitBreakerPriceProvider(p, p.sourceName, set.New(pair), types.CircuitBreakerConfig{
+			MaxFailures: 2,
+			MaxOutliers: 2,
+			Cooldown:    time.Minute,
+		}, zerolog.New(io.Discard))
+		cb.now = func() time.Time { return now }
+		return cb
+	}
+
+	t.Run("opens after consecutive fetch failures", func(t *testing.T) {
+		p := &testPriceProvider{sourceName: "a", prices: map[asset.Pair]float64{pair: 100}}
+		cb := newBreaker(p)
+		p.failFetch()
+		require.True(t, cb.GetPrice(pair).Valid)
+		require.True(t, cb.GetPrice(pair).Valid, "failures are counted per fetch")
+		require.Equal(t, circuitClosed, cb.state)
+		p.failFetch()
+		require.False(t, cb.GetPrice(pair).Valid)
+		require.Equal(t, circuitOpen, cb.state)
+	})
+
+	t.Run("broken symbol does not open the breaker", func(t *testing.T) {
+		p := &testPriceProvider{sourceName: "a", prices: map[asset.Pair]float64{other: 10}}
+		cb := newCircuitBreakerPriceProvider(p, p.sourceName, set.New(pair, other), types.CircuitBreakerConfig{
+			MaxFailures: 2,
+			Cooldown:    time.Minute,
+		}, zerolog.New(io.Discard))
+		for i := 0; i < 3; i++ {
+			p.fetch()
+			require.False(t, cb.GetPrice(pair).Valid)
+			require.True(t, cb.GetPrice(other).Valid)
+		}
+		require.Equal(t, circuitClosed, cb.state)
+	})
+
+	t.Run("successful fetches reset the failures", func(t *testing.T) {
+		p := &testPriceProvider{sourceName: "a", prices: map[asset.Pair]float64{}}
+		cb := newBreaker(p)
+		p.failFetch()
+		require.False(t, cb.GetPrice(pair).Valid)
+		p.fetch()
+		require.False(t, cb.GetPrice(pair).Valid, "invalid prices are not fetch failures")
+		p.failFetch()
+		require.False(t, cb.GetPrice(pair).Valid)
+		require.Equal(t, circuitClosed, cb.state)
+	})
+
+	t.Run("outliers are counted once per fetch", func(t *testing.T) {
+		p := &testPriceProvider{sourceName: "a", prices: map[asset.Pair]float64{pair: 100}}
+		cb := newBreaker(p)
+		cb.recordOutlier(pair)
+		cb.recordOutlier(pair)
+		require.Equal(t, circuitClosed, cb.state)
+		p.fetch()
+		cb.recordAccepted(pair)
+		p.fetch()
+		cb.recordOutlier(pair)
+		require.Equal(t, circuitClosed, cb.state)
+		p.fetch()
+		cb.recordOutlier(pair)
+		require.Equal(t, circuitOpen, cb.state)
+	})
+
+	t.Run("half-open after cooldown", func(t *testing.T) {
+		p := &testPriceProvider{sourceName: "a", prices: map[asset.Pair]float64{pair: 100}}
+		cb := newBreaker(p)
+		cb.recordOutlier(pair)
+		p.fetch()
+		cb.recordOutlier(pair)
+		require.Equal(t, circuitOpen, cb.state)
+
+		cb.now = func() time.Time { return now.Add(time.Minute) }
+		require.True(t, cb.GetPrice(pair).Valid)
+		require.Equal(t, circuitHalfOpen, cb.state)
+		p.fetch()
+		cb.recordAccepted(pair)
+		require.Equal(t, circuitClosed, cb.state)
+	})
+
+	t.Run("failed probe opens again", func(t *testing.T) {
+		p := &testPriceProvider{sourceName: "a", prices: map[asset.Pair]float64{}}
+		cb := newBreaker(p)
+		p.failFetch()
+		p.failFetch()
+		cb.GetPrice(pair)
+		require.Equal(t, circuitOpen, cb.state)
+
+		cb.now = func() time.Time { return now.Add(time.Minute) }
+		p.failFetch()
+		require.False(t, cb.GetPrice(pair).Valid)
+		require.Equal(t, circuitOpen, cb.state)
+		require.False(t, cb.allow(), "cooldown restarts")
+	})
+}
+
+func TestAggregatePriceProviderCircuitBreaker(t *testing.T) {
+	pair := asset.Registry.Pair(denoms.BTC, denoms.USD)
+	providers := []*testPriceProvider{
+		{sourceName: "a", prices: map[asset.Pair]float64{pair: 100}},
+		{sourceName: "b", prices: map[asset.Pair]float64{pair: 101}},
+		{sourceName: "c", prices: map[asset.Pair]float64{pair: 99}},
+		{sourceName: "d", prices: map[asset.Pair]float64{pair: 250}},
+	}
+	config := types.AggregationConfig{
+		Strategy:               types.AggregationMedian,
+		OutlierMaxDeviationPct: 5,
+		CircuitBreaker:         types.CircuitBreakerConfig{MaxOutliers: 2, Cooldown: time.Minute},
+	}
+	pp := newTestAggregatePriceProvider(config, providers...)
+	for _, p := range providers {
+		pp.providers[p.sourceName] = newCircuitBreakerPriceProvider(p, p.sourceName, set.New(pair), config.CircuitBreaker, zerolog.New(io.Discard))
+	}
+
+	for i := 0; i < 2; i++ {
+		for _, p := range providers {
+			p.fetch()
+		}
+		for j := 0; j < 2; j++ {
+			price := pp.GetPrice(pair)
+			require.True(t, price.Valid)
+			require.Equal(t, []string{"a", "b", "c"}, price.Sources)
+		}
+		if i == 0 {
+			require.Equal(t, circuitClosed, pp.providers["d"].(*circuitBreakerPriceProvider).state,
+				"outliers of the same fetch are counted once")
+		}
+	}
+	require.Equal(t, circuitOpen, pp.providers["d"].(*circuitBreakerPriceProvider).state)
+	require.Equal(t, circuitClosed, pp.providers["a"].(*circuitBreakerPriceProvider).state)
+
+	// the source is excluded from aggregation, so it is no longer rejected
+	providers[3].prices[pair] = 100
+	price := pp.GetPrice(pair)
+	require.True(t, price.Valid)
+	require.Equal(t, []string{"a", "b", "c"}, price.Sources)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/NibiruChain/nibiru/v2/x/common/asset"
 	"github.com/rs/zerolog"
 
 	"github.com/NibiruChain/pricefeeder/types"
@@ -20,6 +21,13 @@ type Feeder struct {
 
 	params types.Params
 
+	// priceChange configures the guard against large moves between voting
+	// periods, previousPrices holds the last prices voted for each pair, and
+	// pendingPrices the new levels of the pairs that moved too much.
+	priceChange    types.PriceChangeConfig
+	previousPrices map[asset.Pair]float64
+	pendingPrices  map[asset.Pair]pendingPrice
+
 	EventStream   types.EventStream
 	PricePoster   types.PricePoster
 	PriceProvider types.PriceProvider
@@ -29,16 +37,20 @@ func NewFeeder(
 	eventStream types.EventStream,
 	priceProvider types.PriceProvider,
 	pricePoster types.PricePoster,
+	priceChange types.PriceChangeConfig,
 	logger zerolog.Logger,
 ) *Feeder {
 	f := &Feeder{
-		logger:        logger,
-		stop:          make(chan struct{}),
-		done:          make(chan struct{}),
-		params:        types.Params{},
-		EventStream:   eventStream,
-		PricePoster:   pricePoster,
-		PriceProvider: priceProvider,
+		logger:         logger,
+		stop:           make(chan struct{}),
+		done:           make(chan struct{}),
+		params:         types.Params{},
+		priceChange:    priceChange,
+		previousPrices: map[asset.Pair]float64{},
+		pendingPrices:  map[asset.Pair]pendingPrice{},
+		EventStream:    eventStream,
+		PricePoster:    pricePoster,
+		PriceProvider:  priceProvider,
 	}
 
 	return f
@@ -98,6 +110,9 @@ func (f *Feeder) handleVotingPeriod(vp types.VotingPeriod) {
 	prices := make([]types.Price, len(f.params.Pairs))
 	for i, p := range f.params.Pairs {
 		price := f.PriceProvider.GetPrice(p)
+		if price.Valid {
+			price = f.guardPriceChange(price)
+		}
 		if !price.Valid {
 			f.logger.Err(fmt.Errorf("no valid price")).Str("asset", p.String()).Str("source", price.SourceName)
 			price.Price = types.PriceAbstain
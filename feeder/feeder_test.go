@@ -23,7 +23,7 @@ func TestRunPanics(t *testing.T) {
 	eventStream := mocks.NewMockEventStream(ctrl)
 	eventStream.EXPECT().ParamsUpdate().Return(make(chan types.Params))
 
-	f := NewFeeder(eventStream, priceProvider, pricePoster, zerolog.New(io.Discard))
+	f := NewFeeder(eventStream, priceProvider, pricePoster, types.DefaultPriceChangeConfig(), zerolog.New(io.Discard))
 
 	require.Panics(t, func() {
 		f.Run()
@@ -105,6 +105,10 @@ func initFeeder(t *testing.T) testFeederHarness {
 		PriceProvider: priceProvider,
 		params:        types.Params{},
 		logger:        zerolog.New(io.Discard),
+
+		priceChange:    types.DefaultPriceChangeConfig(),
+		previousPrices: map[asset.Pair]float64{},
+		pendingPrices:  map[asset.Pair]pendingPrice{},
 	}
 	feeder.Run()
 
@@ -121,3 +125,78 @@ func initFeeder(t *testing.T) testFeederHarness {
 		paramsChannel:     paramsChannel,
 	}
 }
+
+func TestPriceChangeGuard(t *testing.T) {
+	pair := asset.Registry.Pair(denoms.BTC, denoms.NUSD)
+	newPrice := func(price float64, depth int, sources ...string) types.Price {
+		return types.Price{Pair: pair, Price: price, Sources: sources, Depth: depth, Valid: true}
+	}
+
+	testCases := []struct {
+		name      string
+		config    types.PriceChangeConfig
+		prices    []types.Price
+		wantValid []bool
+	}{
+		{
+			name:      "disabled",
+			config:    types.DefaultPriceChangeConfig(),
+			prices:    []types.Price{newPrice(100, 1, "a"), newPrice(200, 1, "a")},
+			wantValid: []bool{true, true},
+		},
+		{
+			name:      "small change is voted",
+			config:    types.PriceChangeConfig{MaxChangePct: 10, Action: types.PriceChangeAbstain, SettlePeriods: 3},
+			prices:    []types.Price{newPrice(100, 1, "a"), newPrice(109, 1, "a")},
+			wantValid: []bool{true, true},
+		},
+		{
+			name:      "flash crash abstains, then recovery is voted",
+			config:    types.PriceChangeConfig{MaxChangePct: 10, Action: types.PriceChangeAbstain, SettlePeriods: 3},
+			prices:    []types.Price{newPrice(100, 1, "a"), newPrice(50, 2, "a", "b"), newPrice(100, 1, "a")},
+			wantValid: []bool{true, false, true},
+		},
+		{
+			name:      "lasting move is voted once settled",
+			config:    types.PriceChangeConfig{MaxChangePct: 10, Action: types.PriceChangeAbstain, SettlePeriods: 3},
+			prices:    []types.Price{newPrice(100, 1, "a"), newPrice(150, 1, "a"), newPrice(151, 1, "a"), newPrice(152, 1, "a"), newPrice(155, 1, "a")},
+			wantValid: []bool{true, false, false, true, true},
+		},
+		{
+			name:      "unsettled moves restart the count",
+			config:    types.PriceChangeConfig{MaxChangePct: 10, Action: types.PriceChangeAbstain, SettlePeriods: 2},
+			prices:    []types.Price{newPrice(100, 1, "a"), newPrice(150, 1, "a"), newPrice(200, 1, "a"), newPrice(100, 1, "a"), newPrice(50, 1, "a")},
+			wantValid: []bool{true, false, false, true, false},
+		},
+		{
+			name:      "confirmed by a second source",
+			config:    types.PriceChangeConfig{MaxChangePct: 10, Action: types.PriceChangeConfirm, SettlePeriods: 3},
+			prices:    []types.Price{newPrice(100, 1, "a"), newPrice(50, 2, "a", "b"), newPrice(100, 1, "a")},
+			wantValid: []bool{true, true, false},
+		},
+		{
+			name:   "quote conversion source does not confirm",
+			config: types.PriceChangeConfig{MaxChangePct: 10, Action: types.PriceChangeConfirm, SettlePeriods: 3},
+			// e.g. gateio BTC_USDT converted with kraken USDT/USD
+			prices:    []types.Price{newPrice(100, 1, "gateio"), newPrice(50, 1, "gateio", "kraken")},
+			wantValid: []bool{true, false},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := &Feeder{
+				logger:         zerolog.New(io.Discard),
+				priceChange:    tc.config,
+				previousPrices: map[asset.Pair]float64{},
+				pendingPrices:  map[asset.Pair]pendingPrice{},
+			}
+			for i, price := range tc.prices {
+				got := f.guardPriceChange(price)
+				require.Equal(t, tc.wantValid[i], got.Valid, "price %d", i)
+				if !got.Valid {
+					require.Equal(t, types.PriceAbstain, got.Price)
+				}
+			}
+		})
+	}
+}
@@ -97,13 +97,16 @@ func (s *IntegrationSuite) SetupSuite() {
 				asset.Registry.Pair(denoms.ETH, denoms.NUSD): "tETHUSD",
 			},
 			json.RawMessage{},
+			0,
 			logger,
 		),
 		feeder.DialPricePoster(
 			grpcEndpoint,
 			s.cfg.ChainID,
 			enableTLS,
-			val.ClientCtx.Keyring, val.ValAddress, val.Address, logger),
+			val.ClientCtx.Keyring, val.ValAddress, val.Address,
+			types.ConsensusDeviationConfig{}, logger),
+		types.DefaultPriceChangeConfig(),
 		logger,
 	)
 	s.feeder.Run()
@@ -0,0 +1,105 @@
+package feeder
+
+import (
+	"math"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/NibiruChain/pricefeeder/metrics"
+	"github.com/NibiruChain/pricefeeder/types"
+)
+
+// minConfirmingSources is the number of sources that must quote the pair of a
+// price that moved more than allowed, with the [types.PriceChangeConfirm]
+// action. Sources only converting its quote asset do not count.
+const minConfirmingSources = 2
+
+var priceChangeRejections = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: metrics.PrometheusNamespace,
+	Name:      "price_change_rejections_total",
+	Help:      "The total number of prices abstained because they moved too much since the previous voting period, by pair",
+}, []string{"pair"})
+
+// pendingPrice is the new level of a pair whose price moved more than allowed,
+// with the number of consecutive voting periods it was observed in.
+type pendingPrice struct {
+	price   float64
+	periods int
+}
+
+// guardPriceChange compares a valid price with the price voted for the pair
+// in the previous voting period. If it moved more than allowed, the price is
+// abstained, unless the configured action is to confirm it and the pair is
+// quoted by enough sources. A single flash-crash tick does not become the
+// reference, so its recovery is voted, but a new level that holds for the
+// configured settle periods is voted and becomes the reference.
+func (f *Feeder) guardPriceChange(price types.Price) types.Price {
+	if !f.priceChangeAllowed(price) && !f.priceChangeSettled(price) {
+		priceChangeRejections.WithLabelValues(price.Pair.String()).Inc()
+		price.Price, price.Valid = types.PriceAbstain, false
+		return price
+	}
+	delete(f.pendingPrices, price.Pair)
+	f.previousPrices[price.Pair] = price.Price
+	return price
+}
+
+// priceChangeSettled records a price that moved more than allowed as the
+// pending level of the pair, and reports whether that level held for the
+// configured number of consecutive voting periods.
+func (f *Feeder) priceChangeSettled(price types.Price) bool {
+	pending, ok := f.pendingPrices[price.Pair]
+	if ok && math.Abs(price.Price-pending.price)/pending.price*100 <= f.priceChange.MaxChangePct {
+		pending.periods++
+	} else {
+		pending.periods = 1
+	}
+	pending.price = price.Price
+	f.pendingPrices[price.Pair] = pending
+
+	if pending.periods < f.priceChange.SettlePeriods {
+		return false
+	}
+	f.logger.Info().
+		Str("pair", price.Pair.String()).
+		Float64("price", price.Price).
+		Float64("previous_price", f.previousPrices[price.Pair]).
+		Int("periods", pending.periods).
+		Msg("large price change settled, voting the new level")
+	return true
+}
+
+// priceChangeAllowed reports whether the price can be voted given the price
+// voted for the pair in the previous voting period.
+func (f *Feeder) priceChangeAllowed(price types.Price) bool {
+	previousPrice, hasPrevious := f.previousPrices[price.Pair]
+	maxChangePct := f.priceChange.MaxChangePct
+	if maxChangePct <= 0 || !hasPrevious || previousPrice <= 0 {
+		return true
+	}
+	changePct := math.Abs(price.Price-previousPrice) / previousPrice * 100
+	if changePct <= maxChangePct {
+		return true
+	}
+	if f.priceChange.Action == types.PriceChangeConfirm && price.Depth >= minConfirmingSources {
+		f.logger.Info().
+			Str("pair", price.Pair.String()).
+			Float64("price", price.Price).
+			Float64("previous_price", previousPrice).
+			Float64("change_pct", changePct).
+			Strs("sources", price.Sources).
+			Int("depth", price.Depth).
+			Msg("large price change confirmed by multiple sources")
+		return true
+	}
+
+	f.logger.Warn().
+		Str("pair", price.Pair.String()).
+		Float64("price", price.Price).
+		Float64("previous_price", previousPrice).
+		Float64("change_pct", changePct).
+		Float64("max_change_pct", maxChangePct).
+		Msg("price changed too much since the previous voting period, abstaining")
+	return false
+}
@@ -0,0 +1,43 @@
+package feeder
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/NibiruChain/pricefeeder/metrics"
+	"github.com/NibiruChain/pricefeeder/types"
+)
+
+var priceBoundViolations = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: metrics.PrometheusNamespace,
+	Name:      "price_bound_violations_total",
+	Help:      "The total number of source prices outside of the configured price bounds, by pair, source and action taken",
+}, []string{"pair", "source", "action"})
+
+// guardPrice applies the configured [types.PriceBound] of the pair to a valid
+// source price. Depending on the action of the bound, a price outside of it is
+// clamped, invalidated, or kept as is. Every violation is logged and counted.
+func (a AggregatePriceProvider) guardPrice(price types.Price) types.Price {
+	bound, ok := a.config.PriceBounds[price.Pair]
+	if !ok || !bound.Violated(price.Price) {
+		return price
+	}
+
+	a.logger.Warn().
+		Str("pair", price.Pair.String()).
+		Str("source", price.SourceName).
+		Float64("price", price.Price).
+		Float64("min", bound.Min).
+		Float64("max", bound.Max).
+		Str("action", string(bound.Action)).
+		Msg("price out of bounds")
+	priceBoundViolations.WithLabelValues(price.Pair.String(), price.SourceName, string(bound.Action)).Inc()
+
+	switch bound.Action {
+	case types.PriceBoundClamp:
+		price.Price = bound.Clamp(price.Price)
+	case types.PriceBoundAbstain:
+		price.Price, price.Valid = types.PriceAbstain, false
+	}
+	return price
+}
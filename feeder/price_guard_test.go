@@ -0,0 +1,62 @@
+package feeder
+
+import (
+	"testing"
+
+	"github.com/NibiruChain/nibiru/v2/x/common/asset"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NibiruChain/pricefeeder/types"
+)
+
+func TestAggregatePriceProviderPriceBounds(t *testing.T) {
+	pair := asset.Pair("usda:uusdt")
+	providers := []*testPriceProvider{
+		{sourceName: "a", prices: map[asset.Pair]float64{pair: 0.5}},
+		{sourceName: "b", prices: map[asset.Pair]float64{pair: 0.99}},
+	}
+
+	testCases := []struct {
+		name      string
+		action    types.PriceBoundAction
+		wantPrice float64
+		wantSrcs  []string
+	}{
+		{"clamp", types.PriceBoundClamp, (0.95 + 0.99) / 2, []string{"a", "b"}},
+		{"abstain", types.PriceBoundAbstain, 0.99, []string{"b"}},
+		{"alert", types.PriceBoundAlert, (0.5 + 0.99) / 2, []string{"a", "b"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := types.DefaultAggregationConfig()
+			config.PriceBounds = map[asset.Pair]types.PriceBound{
+				pair: {Min: 0.95, Max: 1.01, Action: tc.action},
+			}
+			pp := newTestAggregatePriceProvider(config, providers...)
+			price := pp.GetPrice(pair)
+			require.True(t, price.Valid)
+			require.InDelta(t, tc.wantPrice, price.Price, 1e-12)
+			require.Equal(t, tc.wantSrcs, price.Sources)
+		})
+	}
+
+	t.Run("abstains when every source is out of bounds", func(t *testing.T) {
+		config := types.DefaultAggregationConfig()
+		config.PriceBounds = map[asset.Pair]types.PriceBound{
+			pair: {Max: 0.1, Action: types.PriceBoundAbstain},
+		}
+		pp := newTestAggregatePriceProvider(config, providers...)
+		require.False(t, pp.GetPrice(pair).Valid)
+	})
+
+	t.Run("applies to inverted prices", func(t *testing.T) {
+		config := types.DefaultAggregationConfig()
+		config.PriceBounds = map[asset.Pair]types.PriceBound{
+			pair.Inverse(): {Max: 1.5, Action: types.PriceBoundClamp},
+		}
+		pp := newTestAggregatePriceProvider(config, providers...)
+		price := pp.GetPrice(pair.Inverse())
+		require.True(t, price.Valid)
+		require.InDelta(t, (1.5+1/0.99)/2, price.Price, 1e-12)
+	})
+}
@@ -0,0 +1,80 @@
+package feeder
+
+import (
+	"time"
+
+	"github.com/NibiruChain/pricefeeder/types"
+)
+
+// priceHistorySize is the number of samples kept per symbol.
+const priceHistorySize = types.PriceHistorySize
+
+// priceHistory is a ring buffer holding the most recent prices of a symbol,
+// ordered by update time.
+type priceHistory struct {
+	samples [priceHistorySize]types.RawPrice
+	start   int // index of the oldest sample
+	len     int
+}
+
+// add appends a sample to the history, evicting the oldest one when full.
+// A sample that is not newer than the latest one replaces it, since sources
+// may report the same update more than once.
+func (h *priceHistory) add(price types.RawPrice) {
+	if h.len > 0 && !price.UpdateTime.After(h.latest().UpdateTime) {
+		h.samples[h.index(h.len-1)] = price
+		return
+	}
+	if h.len < priceHistorySize {
+		h.samples[h.index(h.len)] = price
+		h.len++
+		return
+	}
+	h.samples[h.start] = price
+	h.start = (h.start + 1) % priceHistorySize
+}
+
+// latest returns the most recent sample. The history must not be empty.
+func (h *priceHistory) latest() types.RawPrice {
+	return h.at(h.len - 1)
+}
+
+// at returns the i-th oldest sample.
+func (h *priceHistory) at(i int) types.RawPrice {
+	return h.samples[h.index(i)]
+}
+
+func (h *priceHistory) index(i int) int {
+	return (h.start + i) % priceHistorySize
+}
+
+// twap returns the time-weighted average price over the window ending at now.
+// Each sample is weighted by the time it was the latest price within the
+// window. If no sample covers any time in the window, the latest price is
+// returned. The history must not be empty.
+func (h *priceHistory) twap(window time.Duration, now time.Time) float64 {
+	windowStart := now.Add(-window)
+	end := now
+
+	var weightedSum, totalSeconds float64
+	for i := h.len - 1; i >= 0; i-- {
+		sample := h.at(i)
+		from := sample.UpdateTime
+		if from.Before(windowStart) {
+			from = windowStart
+		}
+		if d := end.Sub(from).Seconds(); d > 0 {
+			weightedSum += sample.Price * d
+			totalSeconds += d
+		}
+		if !sample.UpdateTime.After(windowStart) {
+			break
+		}
+		end = sample.UpdateTime
+	}
+
+	if totalSeconds == 0 {
+		return h.latest().Price
+	}
+	return weightedSum / totalSeconds
+}
@@ -0,0 +1,83 @@
+package feeder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/NibiruChain/pricefeeder/types"
+)
+
+func TestPriceHistory(t *testing.T) {
+	now := time.Now()
+
+	t.Run("evicts oldest samples", func(t *testing.T) {
+		h := &priceHistory{}
+		for i := 0; i < priceHistorySize+10; i++ {
+			h.add(types.RawPrice{Price: float64(i), UpdateTime: now.Add(time.Duration(i) * time.Second)})
+		}
+		require.Equal(t, priceHistorySize, h.len)
+		require.Equal(t, float64(10), h.at(0).Price)
+		require.Equal(t, float64(priceHistorySize+9), h.latest().Price)
+	})
+
+	t.Run("replaces samples that are not newer", func(t *testing.T) {
+		h := &priceHistory{}
+		h.add(types.RawPrice{Price: 1, UpdateTime: now})
+		h.add(types.RawPrice{Price: 2, UpdateTime: now})
+		require.Equal(t, 1, h.len)
+		require.Equal(t, float64(2), h.latest().Price)
+	})
+
+	testCases := []struct {
+		name    string
+		samples []types.RawPrice
+		window  time.Duration
+		want    float64
+	}{
+		{
+			name:    "single sample",
+			samples: []types.RawPrice{{Price: 10, UpdateTime: now.Add(-time.Second)}},
+			window:  time.Minute,
+			want:    10,
+		},
+		{
+			name: "weighted by time",
+			samples: []types.RawPrice{
+				{Price: 10, UpdateTime: now.Add(-40 * time.Second)},
+				{Price: 20, UpdateTime: now.Add(-10 * time.Second)},
+			},
+			window: time.Minute,
+			want:   (10*30 + 20*10) / 40.0,
+		},
+		{
+			name: "sample before the window counts from the window start",
+			samples: []types.RawPrice{
+				{Price: 100, UpdateTime: now.Add(-10 * time.Minute)},
+				{Price: 10, UpdateTime: now.Add(-2 * time.Minute)},
+				{Price: 20, UpdateTime: now.Add(-30 * time.Second)},
+			},
+			window: time.Minute,
+			want:   (10*30 + 20*30) / 60.0,
+		},
+		{
+			name: "spike is damped",
+			samples: []types.RawPrice{
+				{Price: 100, UpdateTime: now.Add(-60 * time.Second)},
+				{Price: 200, UpdateTime: now.Add(-6 * time.Second)},
+			},
+			window: time.Minute,
+			want:   (100*54 + 200*6) / 60.0,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := &priceHistory{}
+			for _, sample := range tc.samples {
+				h.add(sample)
+			}
+			require.InDelta(t, tc.want, h.twap(tc.window, now), 1e-9)
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/rand"
 	"crypto/tls"
 	"fmt"
+	"math"
 	"math/big"
 	"os"
 	"strconv"
@@ -36,6 +37,7 @@ var _ types.PricePoster = (*ClientPricePoster)(nil)
 
 type Oracle interface {
 	AggregatePrevote(context.Context, *oracletypes.QueryAggregatePrevoteRequest, ...grpc.CallOption) (*oracletypes.QueryAggregatePrevoteResponse, error)
+	ExchangeRates(context.Context, *oracletypes.QueryExchangeRatesRequest, ...grpc.CallOption) (*oracletypes.QueryExchangeRatesResponse, error)
 }
 
 type Auth interface {
@@ -63,6 +65,7 @@ func DialPricePoster(
 	keyBase keyring.Keyring,
 	validator sdk.ValAddress,
 	feeder sdk.AccAddress,
+	consensusDeviation types.ConsensusDeviationConfig,
 	logger zerolog.Logger,
 ) *ClientPricePoster {
 	creds := insecure.NewCredentials()
@@ -92,10 +95,11 @@ func DialPricePoster(
 	}
 
 	return &ClientPricePoster{
-		logger:    logger,
-		validator: validator,
-		feeder:    feeder,
-		deps:      deps,
+		logger:             logger,
+		validator:          validator,
+		feeder:             feeder,
+		consensusDeviation: consensusDeviation,
+		deps:               deps,
 	}
 }
 
@@ -105,6 +109,10 @@ type ClientPricePoster struct {
 	validator sdk.ValAddress
 	feeder    sdk.AccAddress
 
+	// consensusDeviation configures the check of our prices against the
+	// last consensus exchange rates before they are prevoted.
+	consensusDeviation types.ConsensusDeviationConfig
+
 	previousPrevote *prevote
 	deps            deps
 }
@@ -125,6 +133,10 @@ func (c *ClientPricePoster) SendPrices(vp types.VotingPeriod, prices []types.Pri
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
+	// the vote revealed in this period must match the previous prevote, so
+	// prices are checked against the consensus before they are prevoted
+	prices = checkConsensusDeviation(ctx, c.deps.oracleClient, prices, c.consensusDeviation, logger)
+
 	newPrevote := newPrevote(prices, c.validator, c.feeder)
 	resp, err := vote(ctx, newPrevote, c.previousPrevote, c.validator, c.feeder, c.deps, logger)
 	if err != nil {
@@ -146,6 +158,66 @@ func (c *ClientPricePoster) GetOracleClient() Oracle {
 	return c.deps.oracleClient
 }
 
+var consensusDeviations = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: metrics.PrometheusNamespace,
+	Name:      "consensus_deviations_total",
+	Help:      "The total number of prices deviating from the last consensus exchange rate by more than the threshold, by pair and whether the pair was abstained",
+}, []string{"pair", "abstained"})
+
+// checkConsensusDeviation compares the prices with the last exchange rates the
+// oracle came to consensus on. Prices deviating by more than the configured
+// threshold are logged and counted, and switched to abstain if configured.
+// Pairs without a consensus exchange rate are not checked, and the prices are
+// returned unchanged if the exchange rates cannot be queried.
+func checkConsensusDeviation(
+	ctx context.Context,
+	oracleClient Oracle,
+	prices []types.Price,
+	config types.ConsensusDeviationConfig,
+	logger zerolog.Logger,
+) []types.Price {
+	if config.MaxDeviationPct <= 0 {
+		return prices
+	}
+	resp, err := oracleClient.ExchangeRates(ctx, &oracletypes.QueryExchangeRatesRequest{})
+	if err != nil {
+		logger.Err(err).Msg("failed to get exchange rates from chain, skipping consensus deviation check")
+		return prices
+	}
+	consensusRates := make(map[string]float64, len(resp.ExchangeRates))
+	for _, rate := range resp.ExchangeRates {
+		if v, err := rate.ExchangeRate.Float64(); err == nil && v > 0 {
+			consensusRates[rate.Pair.String()] = v
+		}
+	}
+
+	checked := make([]types.Price, len(prices))
+	for i, price := range prices {
+		checked[i] = price
+		consensusRate, ok := consensusRates[price.Pair.String()]
+		if !ok || !price.Valid || price.Price == types.PriceAbstain {
+			continue
+		}
+		deviationPct := math.Abs(price.Price-consensusRate) / consensusRate * 100
+		if deviationPct <= config.MaxDeviationPct {
+			continue
+		}
+
+		logger.Warn().
+			Str("pair", price.Pair.String()).
+			Float64("price", price.Price).
+			Float64("consensus_rate", consensusRate).
+			Float64("deviation_pct", deviationPct).
+			Bool("abstain", config.Abstain).
+			Msg("price deviates from the consensus exchange rate")
+		consensusDeviations.WithLabelValues(price.Pair.String(), strconv.FormatBool(config.Abstain)).Inc()
+		if config.Abstain {
+			checked[i].Price, checked[i].Valid = types.PriceAbstain, false
+		}
+	}
+	return checked
+}
+
 // TryUntilDone will try to execute the given function until it succeeds or the
 // context is cancelled.
 func TryUntilDone(
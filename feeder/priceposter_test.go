@@ -0,0 +1,77 @@
+package feeder
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	sdkmath "cosmossdk.io/math"
+	"github.com/NibiruChain/nibiru/v2/x/common/asset"
+	oracletypes "github.com/NibiruChain/nibiru/v2/x/oracle/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/NibiruChain/pricefeeder/types"
+)
+
+var _ Oracle = (*testOracle)(nil)
+
+// testOracle is an Oracle returning fixed consensus exchange rates.
+type testOracle struct {
+	exchangeRates oracletypes.ExchangeRateTuples
+	err           error
+}
+
+func (o testOracle) AggregatePrevote(context.Context, *oracletypes.QueryAggregatePrevoteRequest, ...grpc.CallOption) (*oracletypes.QueryAggregatePrevoteResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (o testOracle) ExchangeRates(context.Context, *oracletypes.QueryExchangeRatesRequest, ...grpc.CallOption) (*oracletypes.QueryExchangeRatesResponse, error) {
+	if o.err != nil {
+		return nil, o.err
+	}
+	return &oracletypes.QueryExchangeRatesResponse{ExchangeRates: o.exchangeRates}, nil
+}
+
+func TestCheckConsensusDeviation(t *testing.T) {
+	btc, eth, nibi := asset.Pair("ubtc:uusd"), asset.Pair("ueth:uusd"), asset.Pair("unibi:uusd")
+	oracle := testOracle{exchangeRates: oracletypes.ExchangeRateTuples{
+		{Pair: btc, ExchangeRate: sdkmath.LegacyNewDec(100_000)},
+		{Pair: eth, ExchangeRate: sdkmath.LegacyNewDec(4_000)},
+	}}
+	prices := []types.Price{
+		{Pair: btc, Price: 100_500, Valid: true},
+		{Pair: eth, Price: 3_000, Valid: true},
+		{Pair: nibi, Price: 0.02, Valid: true},
+	}
+	logger := zerolog.New(io.Discard)
+
+	t.Run("disabled", func(t *testing.T) {
+		got := checkConsensusDeviation(context.Background(), oracle, prices, types.ConsensusDeviationConfig{}, logger)
+		require.Equal(t, prices, got)
+	})
+
+	t.Run("alert only", func(t *testing.T) {
+		config := types.ConsensusDeviationConfig{MaxDeviationPct: 5}
+		got := checkConsensusDeviation(context.Background(), oracle, prices, config, logger)
+		require.Equal(t, prices, got)
+	})
+
+	t.Run("abstain", func(t *testing.T) {
+		config := types.ConsensusDeviationConfig{MaxDeviationPct: 5, Abstain: true}
+		got := checkConsensusDeviation(context.Background(), oracle, prices, config, logger)
+		require.Equal(t, prices[0], got[0])
+		require.False(t, got[1].Valid)
+		require.Equal(t, types.PriceAbstain, got[1].Price)
+		require.Equal(t, prices[2], got[2], "pairs without consensus rate are not checked")
+		require.True(t, prices[1].Valid, "input prices are not modified")
+	})
+
+	t.Run("query failure", func(t *testing.T) {
+		config := types.ConsensusDeviationConfig{MaxDeviationPct: 5, Abstain: true}
+		got := checkConsensusDeviation(context.Background(), testOracle{err: errors.New("unavailable")}, prices, config, logger)
+		require.Equal(t, prices, got)
+	})
+}
@@ -3,6 +3,8 @@ package feeder
 import (
 	"encoding/json"
 	"fmt"
+	"math"
+	"sort"
 	"sync"
 	"time"
 
@@ -36,17 +38,27 @@ type PriceProvider struct {
 	source              types.Source
 	sourceName          string
 	pairToSymbolMapping map[asset.Pair]types.Symbol
-	lastPricesMutex     sync.Mutex
-	lastPrices          map[types.Symbol]types.RawPrice
+	// twapWindow is the window of the time-weighted average price returned
+	// instead of the last price, or zero to return the last price.
+	twapWindow time.Duration
+	// staleness holds the maximum age of the prices of the source.
+	staleness    types.StalenessConfig
+	historyMutex sync.Mutex
+	history      map[types.Symbol]*priceHistory
+	// fetches counts the updates of the source, and fetchFailures the
+	// consecutive empty updates, which report failed fetches.
+	fetches       uint64
+	fetchFailures int
 }
 
 // NewPriceProvider returns a types.PriceProvider given the price source we want
 // to gather prices from, the mapping between nibiru asset.Pair and the source's
-// symbols, and a zerolog.Logger instance.
+// symbols, the TWAP window (zero for the last price), and a zerolog.Logger instance.
 func NewPriceProvider(
 	sourceName string,
 	pairToSymbolMap map[asset.Pair]types.Symbol,
 	config json.RawMessage,
+	twapWindow time.Duration,
 	logger zerolog.Logger,
 ) types.PriceProvider {
 	var source types.Source
@@ -56,7 +68,20 @@ func NewPriceProvider(
 		symbols.Add(s)
 	}
 
-	source, err := sources.GetRegisteredSource(sourceName, symbols, config, logger)
+	staleness, err := types.ParseStalenessConfig(config)
+	if err != nil {
+		logger.
+			Warn().
+			Str("source", sourceName).
+			Err(err).
+			Msg("invalid staleness config, using the default max price age")
+		staleness = types.StalenessConfig{}
+	}
+	if staleness.MaxPriceAge == 0 {
+		staleness.MaxPriceAge = types.Duration(sources.DefaultMaxPriceAge(sourceName))
+	}
+
+	source, err = sources.GetRegisteredSource(sourceName, symbols, config, logger)
 	if err != nil {
 		logger.
 			Warn().
@@ -64,13 +89,21 @@ func NewPriceProvider(
 		return types.NullPriceProvider{}
 	}
 
-	return newPriceProvider(source, sourceName, pairToSymbolMap, logger)
+	return newPriceProvider(source, sourceName, pairToSymbolMap, twapWindow, staleness, logger)
 }
 
-// newPriceProvider returns a raw *PriceProvider given a Source implementer, the source name and the
-// map of nibiru asset.Pair to Source's symbols, plus the zerolog.Logger instance.
+// newPriceProvider returns a raw *PriceProvider given a Source implementer, the source name, the
+// map of nibiru asset.Pair to Source's symbols, the TWAP window and the maximum age of prices,
+// plus the zerolog.Logger instance.
 // Exists for testing purposes.
-func newPriceProvider(source types.Source, sourceName string, pairToSymbolsMap map[asset.Pair]types.Symbol, logger zerolog.Logger) *PriceProvider {
+func newPriceProvider(
+	source types.Source,
+	sourceName string,
+	pairToSymbolsMap map[asset.Pair]types.Symbol,
+	twapWindow time.Duration,
+	staleness types.StalenessConfig,
+	logger zerolog.Logger,
+) *PriceProvider {
 	pp := &PriceProvider{
 		logger:              logger.With().Str("component", "price-provider").Str("source", sourceName).Logger(),
 		stopSignal:          make(chan struct{}),
@@ -78,8 +111,10 @@ func newPriceProvider(source types.Source, sourceName string, pairToSymbolsMap m
 		source:              source,
 		sourceName:          sourceName,
 		pairToSymbolMapping: pairToSymbolsMap,
-		lastPricesMutex:     sync.Mutex{},
-		lastPrices:          map[types.Symbol]types.RawPrice{},
+		twapWindow:          twapWindow,
+		staleness:           staleness,
+		historyMutex:        sync.Mutex{},
+		history:             map[types.Symbol]*priceHistory{},
 	}
 
 	go pp.loop()
@@ -88,8 +123,8 @@ func newPriceProvider(source types.Source, sourceName string, pairToSymbolsMap m
 }
 
 // loop runs in a background goroutine and continuously listens for price updates
-// from the source. It updates the lastPrices map with new data and handles
-// shutdown signals. The loop exits when stopSignal is closed, ensuring proper
+// from the source. It appends new data to the price history, counts empty updates as failed fetches,
+// and handles shutdown signals. The loop exits when stopSignal is closed, ensuring proper
 // cleanup of the source and done channel.
 func (p *PriceProvider) loop() {
 	defer close(p.done)
@@ -100,11 +135,22 @@ func (p *PriceProvider) loop() {
 		case <-p.stopSignal:
 			return
 		case updates := <-p.source.PriceUpdates():
-			p.lastPricesMutex.Lock()
+			p.historyMutex.Lock()
 			for symbol, price := range updates {
-				p.lastPrices[symbol] = price
+				history, ok := p.history[symbol]
+				if !ok {
+					history = &priceHistory{}
+					p.history[symbol] = history
+				}
+				history.add(price)
+			}
+			p.fetches++
+			if len(updates) == 0 {
+				p.fetchFailures++
+			} else {
+				p.fetchFailures = 0
 			}
-			p.lastPricesMutex.Unlock()
+			p.historyMutex.Unlock()
 		}
 	}
 }
@@ -112,6 +158,8 @@ func (p *PriceProvider) loop() {
 // GetPrice returns the types.Price for the given asset.Pair
 // in case price has expired, or for some reason it's impossible to
 // get the last available price, then an invalid types.Price is returned.
+// When a TWAP window is configured, the time-weighted average of the recent
+// prices is returned instead of the last price.
 func (p *PriceProvider) GetPrice(pair asset.Pair) types.Price {
 	symbol, symbolExists := p.pairToSymbolMapping[pair]
 	// in case this is an unknown symbol, which might happen
@@ -127,28 +175,61 @@ func (p *PriceProvider) GetPrice(pair asset.Pair) types.Price {
 		}
 	}
 
-	p.lastPricesMutex.Lock()
-	price, priceExists := p.lastPrices[symbol]
-	p.lastPricesMutex.Unlock()
+	var price types.RawPrice
+	p.historyMutex.Lock()
+	history, priceExists := p.history[symbol]
+	if priceExists {
+		price = history.latest()
+		if p.twapWindow > 0 {
+			price.Price = history.twap(p.twapWindow, time.Now())
+		}
+	}
+	p.historyMutex.Unlock()
+
+	maxPriceAge := p.staleness.MaxPriceAgeFor(pair)
+	valid := isValid(price, priceExists, maxPriceAge)
+	if priceExists && !valid {
+		p.logger.Debug().
+			Str("pair", pair.String()).
+			Time("update_time", price.UpdateTime).
+			Dur("max_price_age", maxPriceAge).
+			Msg("stale price")
+		stalePrices.WithLabelValues(p.sourceName, pair.String()).Inc()
+	}
 
 	return types.Price{
 		Pair:       pair,
 		Price:      price.Price,
 		SourceName: p.sourceName,
-		Valid:      isValid(price, priceExists),
+		Volume:     price.Volume,
+		Valid:      valid,
 	}
 }
 
+// fetchStatus returns the number of updates of the source so far, and the
+// number of consecutive empty updates, i.e. failed fetches.
+func (p *PriceProvider) fetchStatus() (fetches uint64, failures int) {
+	p.historyMutex.Lock()
+	defer p.historyMutex.Unlock()
+	return p.fetches, p.fetchFailures
+}
+
 func (p *PriceProvider) Close() {
 	close(p.stopSignal)
 	<-p.done
 }
 
+var stalePrices = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: metrics.PrometheusNamespace,
+	Name:      "stale_prices_total",
+	Help:      "The total number of prices rejected because they are older than the max price age, by source and pair",
+}, []string{"source", "pair"})
+
 // isValid determines whether a price is valid based on whether it was found and
-// whether it was updated within the [types.PriceTimeout] window. Prices that are
-// missing or older than [types.PriceTimeout] are considered invalid.
-func isValid(price types.RawPrice, found bool) bool {
-	return found && time.Since(price.UpdateTime) < types.PriceTimeout
+// whether it was updated within the maxPriceAge window. Prices that are
+// missing or older than maxPriceAge are considered invalid.
+func isValid(price types.RawPrice, found bool, maxPriceAge time.Duration) bool {
+	return found && time.Since(price.UpdateTime) < maxPriceAge
 }
 
 // -------------------------------------------------
@@ -158,29 +239,44 @@ func isValid(price types.RawPrice, found bool) bool {
 // AggregatePriceProvider aggregates multiple price providers
 // and queries them for prices.
 type AggregatePriceProvider struct {
-	// providers: A set of providers implemented using a map to zero size
-	// empty struct. Using a map gives us random order of iteration, the
-	// intended behavior (since golang's map range is unordered)
-	providers map[types.PriceProvider]struct{}
-	logger    zerolog.Logger
+	// providers maps the source names to their price provider.
+	providers map[string]types.PriceProvider
+	// sourceNames are the names of the providers, sorted, so that they are
+	// always queried in the same order.
+	sourceNames []string
+	// graph holds the pairs quoted by the providers, used to route
+	// requested pairs through intermediate quote assets.
+	graph  quoteGraph
+	config types.AggregationConfig
+	logger zerolog.Logger
 }
 
 // NewAggregatePriceProvider instantiates a new AggregatePriceProvider instance
-// given multiple PriceProvider.
+// given multiple PriceProvider and the strategy used to aggregate their prices.
 func NewAggregatePriceProvider(
 	sourcesToPairSymbolMap map[string]map[asset.Pair]types.Symbol,
 	sourceConfigMap map[string]json.RawMessage,
+	aggregationConfig types.AggregationConfig,
 	logger zerolog.Logger,
 ) types.PriceProvider {
-	providers := make(map[types.PriceProvider]struct{})
+	providers := make(map[string]types.PriceProvider)
+	graph := newQuoteGraph()
 	invalidSources := []string{}
 	for sourceName, pairToSymbolMap := range sourcesToPairSymbolMap {
-		pp := NewPriceProvider(sourceName, pairToSymbolMap, sourceConfigMap[sourceName], logger)
+		pp := NewPriceProvider(sourceName, pairToSymbolMap, sourceConfigMap[sourceName], aggregationConfig.TWAPWindow, logger)
 		if _, isNull := pp.(types.NullPriceProvider); isNull {
 			invalidSources = append(invalidSources, sourceName)
 			continue
 		}
-		providers[pp] = struct{}{}
+		pairs := set.New[asset.Pair]()
+		for pair := range pairToSymbolMap {
+			graph.addPair(pair)
+			pairs.Add(pair)
+		}
+		if aggregationConfig.CircuitBreaker.Enabled() {
+			pp = newCircuitBreakerPriceProvider(pp, sourceName, pairs, aggregationConfig.CircuitBreaker, logger)
+		}
+		providers[sourceName] = pp
 	}
 
 	if len(providers) != len(sourcesToPairSymbolMap) {
@@ -192,9 +288,29 @@ func NewAggregatePriceProvider(
 			Msg(fmt.Sprintf("no price providers available: { invalidSources: %#v }", invalidSources))
 	}
 
+	return newAggregatePriceProvider(providers, graph, aggregationConfig, logger)
+}
+
+// newAggregatePriceProvider returns an AggregatePriceProvider given the price
+// providers by source name and the graph of the pairs they quote.
+func newAggregatePriceProvider(
+	providers map[string]types.PriceProvider,
+	graph quoteGraph,
+	aggregationConfig types.AggregationConfig,
+	logger zerolog.Logger,
+) AggregatePriceProvider {
+	sourceNames := make([]string, 0, len(providers))
+	for sourceName := range providers {
+		sourceNames = append(sourceNames, sourceName)
+	}
+	sort.Strings(sourceNames)
+
 	return AggregatePriceProvider{
-		logger:    logger.With().Str("component", "aggregate-price-provider").Logger(),
-		providers: providers,
+		logger:      logger.With().Str("component", "aggregate-price-provider").Logger(),
+		providers:   providers,
+		sourceNames: sourceNames,
+		graph:       graph,
+		config:      aggregationConfig,
 	}
 }
 
@@ -204,100 +320,279 @@ var aggregatePriceProvider = promauto.NewCounterVec(prometheus.CounterOpts{
 	Help:      "The total number prices provided by the aggregate price provider, by pair, source, and success status",
 }, []string{"pair", "source", "success"})
 
-// GetPrice fetches the first available and correct price from the wrapped PriceProviders.
-// Iteration is exhaustive and random.
+// GetPrice aggregates the prices of the wrapped PriceProviders for the given pair
+// according to the configured [types.AggregationStrategy]. Pairs that are not
+// quoted directly are routed through intermediate quote assets, and derived
+// pairs are computed from the aggregated prices of their operand pairs.
 // If no correct PriceResponse is found, then an invalid PriceResponse is returned.
 func (a AggregatePriceProvider) GetPrice(pair asset.Pair) types.Price {
-	switch pairStr := pair.String(); pairStr {
-	// SPECIAL CASE - stNIBI
-	case "ustnibi:uusd":
-		// fetch unibi:uusd first to calculate the ustnibi:unibi price
-
-		unibiUusdPrice := types.PriceAbstain // default to -1 to indicate we haven't found a valid price yet
-		for p := range a.providers {
-			price := p.GetPrice("unibi:uusd")
-			if !price.Valid {
+	if derivedPair, isDerived := a.config.DerivedPairs[pair]; isDerived {
+		return a.derive(pair, derivedPair)
+	}
+	return a.routePrice(pair)
+}
+
+// derive computes the price of a derived pair from the prices of its operand
+// pairs. The pair is abstained if any operand has no valid price, or if the
+// operand with the fewest sources does not reach the quorum of the pair.
+func (a AggregatePriceProvider) derive(pair asset.Pair, derivedPair types.DerivedPair) types.Price {
+	var (
+		price      float64
+		sourceName string
+		depth      int
+	)
+	sources := set.New[string]()
+	for i, operandPair := range derivedPair.Pairs {
+		operand := a.GetPrice(operandPair)
+		if !operand.Valid || operand.Price <= 0 {
+			a.logger.Warn().
+				Str("pair", pair.String()).
+				Str("operand", operandPair.String()).
+				Msg("no valid price found for derived pair operand")
+			return a.missingPrice(pair)
+		}
+		sources.AddMulti(operand.Sources...)
+		if i == 0 || operand.Depth < depth {
+			depth = operand.Depth
+		}
+
+		if i == 0 {
+			price, sourceName = operand.Price, operand.SourceName
+			continue
+		}
+		switch derivedPair.Operation {
+		case types.DerivedPairMultiply:
+			price *= operand.Price
+		case types.DerivedPairDivide:
+			price /= operand.Price
+		}
+	}
+
+	if minSources := a.config.MinSourcesFor(pair); depth < minSources {
+		a.logger.Debug().
+			Str("pair", pair.String()).
+			Int("sources", depth).
+			Int("min_sources", minSources).
+			Msg("not enough sources to reach quorum")
+		return a.missingPrice(pair)
+	}
+
+	sourceNames := sources.ToSlice()
+	sort.Strings(sourceNames)
+	return types.Price{
+		Pair:       pair,
+		Price:      price,
+		SourceName: sourceName, // use the source of the first operand, e.g. ustnibi for ustnibi:uusd
+		Sources:    sourceNames,
+		Depth:      depth,
+		Valid:      true,
+	}
+}
+
+// sourcePrices asks the wrapped PriceProviders for the price of a pair used to
+// convert along a route, and returns the valid answers, at most one per
+// source, within the price bounds and without outliers. Sources quoting the
+// inverse pair contribute their inverted price.
+//
+// Sources are queried tier by tier, in the order configured for the pair, and
+// lower tiers are only used while the higher ones do not reach the quorum of
+// the pair. Without tiers, every source is queried in order of name.
+func (a AggregatePriceProvider) sourcePrices(pair asset.Pair) []types.Price {
+	tiers := a.tiers(pair)
+	minSources := a.config.MinSourcesFor(pair)
+
+	var validPrices []types.Price
+	seenSources := set.New[string]()
+	for i, tier := range tiers {
+		for _, sourceName := range tier {
+			p, ok := a.providers[sourceName]
+			if !ok {
+				continue
+			}
+			price, ok := a.sourcePrice(p, pair)
+			if !ok || seenSources.Has(price.SourceName) {
 				continue
 			}
+			seenSources.Add(price.SourceName)
+			validPrices = append(validPrices, price)
+		}
 
-			unibiUusdPrice = price.Price
+		if len(a.filterOutliers(validPrices)) >= minSources {
 			break
 		}
+		if i < len(tiers)-1 {
+			a.logger.Debug().
+				Str("pair", pair.String()).
+				Int("tier", i+1).
+				Int("sources", len(validPrices)).
+				Int("min_sources", minSources).
+				Msg("source tier below quorum, falling back to next tier")
+		}
+	}
 
-		if unibiUusdPrice <= 0 {
-			// if we can't find a valid unibi:uusd price, return an invalid price
-			a.logger.Warn().Str("pair", pair.String()).Msg("no valid price found")
-			aggregatePriceProvider.WithLabelValues("ustnibi:uusd", "missing", "false").Inc()
-			return types.Price{
-				SourceName: "missing",
-				Pair:       pair,
-				Price:      types.PriceAbstain,
-				Valid:      false,
-			}
+	return a.rejectOutliers(pair, validPrices)
+}
+
+// tiers returns the source tiers of the pair, or of its inverse, in priority
+// order. Pairs without configured tiers use every source in a single tier.
+func (a AggregatePriceProvider) tiers(pair asset.Pair) [][]string {
+	if tiers, ok := a.config.SourceTiers[pair]; ok {
+		return tiers
+	}
+	if tiers, ok := a.config.SourceTiers[pair.Inverse()]; ok {
+		return tiers
+	}
+	return [][]string{a.sourceNames}
+}
+
+// sourcePrice returns the valid price of the pair from a single provider,
+// within the price bounds. If the pair is not quoted as is, the inverse pair
+// is used and its price inverted.
+func (a AggregatePriceProvider) sourcePrice(p types.PriceProvider, pair asset.Pair) (types.Price, bool) {
+	price := types.Price{Valid: false}
+	if a.graph.quotes(pair) {
+		price = p.GetPrice(pair)
+		if price.Valid && price.Price <= 0 {
+			a.logger.Warn().
+				Str("pair", pair.String()).
+				Str("source", price.SourceName).
+				Float64("price", price.Price).
+				Msg("non-positive source price, ignoring")
+			price.Valid = false
+		}
+	}
+	if !price.Valid && a.graph.quotes(pair.Inverse()) {
+		if inverse := p.GetPrice(pair.Inverse()); inverse.Valid && inverse.Price > 0 {
+			// the base volume of the inverse pair is the quote volume of the pair
+			price = inverse
+			price.Pair, price.Price, price.Volume = pair, 1/inverse.Price, inverse.Volume*inverse.Price
 		}
+	}
+	if price.Valid {
+		price = a.guardPrice(price)
+	}
+	return price, price.Valid
+}
 
-		// now we can calculate the ustnibi:unibi price
-		for p := range a.providers {
-			price := p.GetPrice("ustnibi:unibi")
-			if !price.Valid {
-				continue
-			}
+// combine reduces the prices of multiple sources for the same pair to a single
+// value according to the configured strategy.
+func (a AggregatePriceProvider) combine(prices []types.Price) (price float64, sourceNames []string) {
+	if a.config.Strategy == types.AggregationFirstValid {
+		// legacy behavior: return the first valid price, in source order
+		return prices[0].Price, []string{prices[0].SourceName}
+	}
 
-			return types.Price{
-				Pair:       pair,
-				Price:      price.Price * unibiUusdPrice, // ustnibi:uusd = ustnibi:unibi * unibi:uusd
-				SourceName: price.SourceName,             // use the source of ustnibi
-				Valid:      true,
-			}
+	values := make([]float64, len(prices))
+	volumes := make([]float64, len(prices))
+	sourceNames = make([]string, len(prices))
+	for i, price := range prices {
+		values[i] = price.Price
+		volumes[i] = price.Volume
+		sourceNames[i] = price.SourceName
+	}
+	if a.config.Strategy == types.AggregationVWAP {
+		if vwap, ok := volumeWeightedAverage(values, volumes); ok {
+			return vwap, sourceNames
 		}
+		a.logger.Debug().
+			Str("pair", prices[0].Pair.String()).
+			Strs("sources", sourceNames).
+			Msg("volume not reported by every source, using median")
+	}
+	return median(values), sourceNames
+}
 
-	case "susda:usd":
-		priceSusdaUsda := a.GetPrice("susda:usda")
-		if !priceSusdaUsda.Valid || priceSusdaUsda.Price <= 0 {
-			// if we can't find a valid susda:usda price, return an invalid price
-			a.logger.Warn().Str("pair", pairStr).Msg("no valid price found")
-			aggregatePriceProvider.WithLabelValues(pairStr, "missing", "false").Inc()
-			return types.Price{
-				SourceName: "missing",
-				Pair:       pair,
-				Price:      types.PriceAbstain,
-				Valid:      false,
+var outlierRejections = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: metrics.PrometheusNamespace,
+	Name:      "outlier_rejections_total",
+	Help:      "The total number of source prices rejected as outliers before aggregation, by pair and source",
+}, []string{"pair", "source"})
+
+// minSamplesForOutliers is the minimum number of source prices required to
+// reject outliers. With fewer samples there is no majority to tell which
+// source is misbehaving.
+const minSamplesForOutliers = 3
+
+// rejectOutliers drops the prices that are further away from the cross-source
+// median than allowed by the configured standard deviation or percentage
+// bands. Every rejection is logged and counted, and reported to the circuit
+// breaker of the source.
+func (a AggregatePriceProvider) rejectOutliers(pair asset.Pair, prices []types.Price) []types.Price {
+	accepted := a.filterOutliers(prices)
+	if len(accepted) == len(prices) {
+		for _, price := range prices {
+			if breaker, ok := a.providers[price.SourceName].(*circuitBreakerPriceProvider); ok {
+				breaker.recordAccepted(pair)
 			}
 		}
+		return prices
+	}
 
-		priceUsdaUsd := a.GetPrice("usda:usd")
-		if priceUsdaUsd.Valid && priceUsdaUsd.Price > 0 {
-			return types.Price{
-				Pair:       pair,
-				Price:      priceSusdaUsda.Price * priceUsdaUsd.Price,
-				SourceName: priceSusdaUsda.SourceName,
-				Valid:      true,
+	values := make([]float64, len(prices))
+	for i, price := range prices {
+		values[i] = price.Price
+	}
+	medianPrice := median(values)
+	acceptedSources := set.New[string]()
+	for _, price := range accepted {
+		acceptedSources.Add(price.SourceName)
+	}
+	for _, price := range prices {
+		breaker, hasBreaker := a.providers[price.SourceName].(*circuitBreakerPriceProvider)
+		if acceptedSources.Has(price.SourceName) {
+			if hasBreaker {
+				breaker.recordAccepted(pair)
 			}
+			continue
 		}
-
-		// if either price is invalid, return an invalid price
-		a.logger.Warn().Str("pair", pairStr).Msg("no valid price found")
-		aggregatePriceProvider.WithLabelValues(pairStr, "missing", "false").Inc()
-		return types.Price{
-			SourceName: "missing",
-			Pair:       pair,
-			Price:      types.PriceAbstain,
-			Valid:      false,
+		a.logger.Warn().
+			Str("pair", pair.String()).
+			Str("source", price.SourceName).
+			Float64("price", price.Price).
+			Float64("median", medianPrice).
+			Float64("deviation_pct", math.Abs(price.Price-medianPrice)/medianPrice*100).
+			Msg("rejected outlier price")
+		outlierRejections.WithLabelValues(pair.String(), price.SourceName).Inc()
+		if hasBreaker {
+			breaker.recordOutlier(pair)
 		}
+	}
+	return accepted
+}
 
-	default:
-		// for all other price pairs, iterate randomly, if we find a valid price, we return it
-		// otherwise we go onto the next PriceProvider to ask for prices.
-		for p := range a.providers {
-			price := p.GetPrice(pair)
-			if price.Valid {
-				aggregatePriceProvider.WithLabelValues(pair.String(), price.SourceName, "true").Inc()
-				return price
-			}
+// filterOutliers returns the prices that are within the configured standard
+// deviation and percentage bands around the cross-source median. Outliers are
+// not rejected with the first_valid strategy.
+func (a AggregatePriceProvider) filterOutliers(prices []types.Price) []types.Price {
+	maxStdDev, maxDeviationPct := a.config.OutlierMaxStdDev, a.config.OutlierMaxDeviationPct
+	if a.config.Strategy == types.AggregationFirstValid ||
+		len(prices) < minSamplesForOutliers || (maxStdDev <= 0 && maxDeviationPct <= 0) {
+		return prices
+	}
+
+	values := make([]float64, len(prices))
+	for i, price := range prices {
+		values[i] = price.Price
+	}
+	medianPrice := median(values)
+	stdDev := stdDev(values)
+
+	accepted := make([]types.Price, 0, len(prices))
+	for _, price := range prices {
+		deviation := math.Abs(price.Price - medianPrice)
+		deviationPct := deviation / medianPrice * 100
+		tooManyStdDevs := maxStdDev > 0 && stdDev > 0 && deviation > maxStdDev*stdDev
+		tooFarPct := maxDeviationPct > 0 && deviationPct > maxDeviationPct
+		if !tooManyStdDevs && !tooFarPct {
+			accepted = append(accepted, price)
 		}
 	}
+	return accepted
+}
 
-	// if we reach here no valid symbols were found
+// missingPrice logs and returns the invalid price used when no valid price
+// could be found for the pair.
+func (a AggregatePriceProvider) missingPrice(pair asset.Pair) types.Price {
 	a.logger.Warn().Str("pair", pair.String()).Msg("no valid price found")
 	aggregatePriceProvider.WithLabelValues(pair.String(), "missing", "false").Inc()
 	return types.Price{
@@ -309,7 +604,51 @@ func (a AggregatePriceProvider) GetPrice(pair asset.Pair) types.Price {
 }
 
 func (a AggregatePriceProvider) Close() {
-	for p := range a.providers {
+	for _, p := range a.providers {
 		p.Close()
 	}
 }
+
+// median returns the median of the given values. For an even number of
+// values, the mean of the two middle values is returned.
+func median(values []float64) float64 {
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// volumeWeightedAverage returns the average of the values weighted by the
+// volumes. It returns false if any volume is not positive, since a source
+// without volume cannot be weighted against the others.
+func volumeWeightedAverage(values, volumes []float64) (float64, bool) {
+	var weightedSum, totalVolume float64
+	for i, v := range values {
+		if volumes[i] <= 0 || math.IsInf(volumes[i], 0) || math.IsNaN(volumes[i]) {
+			return 0, false
+		}
+		weightedSum += v * volumes[i]
+		totalVolume += volumes[i]
+	}
+	return weightedSum / totalVolume, true
+}
+
+// stdDev returns the population standard deviation of the given values.
+func stdDev(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+
+	var sumSquares float64
+	for _, v := range values {
+		sumSquares += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(sumSquares / float64(len(values)))
+}
@@ -49,6 +49,7 @@ func TestPriceProvider(t *testing.T) {
 			sources.SourceNameBitfinex,
 			map[asset.Pair]types.Symbol{asset.Registry.Pair(denoms.BTC, denoms.NUSD): "tBTCUSD"},
 			json.RawMessage{},
+			0,
 			zerolog.New(io.Discard),
 		)
 		defer pp.Close()
@@ -67,6 +68,7 @@ func TestPriceProvider(t *testing.T) {
 			sources.SourceNameErisProtocol,
 			map[asset.Pair]types.Symbol{asset.NewPair("ustnibi", denoms.NIBI): "ustnibi:unibi"},
 			json.RawMessage{},
+			0,
 			zerolog.New(io.Discard),
 		)
 		defer pp.Close()
@@ -85,6 +87,7 @@ func TestPriceProvider(t *testing.T) {
 				"unknown",
 				nil,
 				nil,
+				0,
 				zerolog.New(io.Discard),
 			)
 			_, isNull := pp.(types.NullPriceProvider)
@@ -93,7 +96,7 @@ func TestPriceProvider(t *testing.T) {
 	})
 
 	t.Run("returns invalid price on unknown AssetPair", func(t *testing.T) {
-		pp := newPriceProvider(testAsyncSource{}, "test", map[asset.Pair]types.Symbol{}, zerolog.New(io.Discard))
+		pp := newPriceProvider(testAsyncSource{}, "test", map[asset.Pair]types.Symbol{}, 0, types.StalenessConfig{}, zerolog.New(io.Discard))
 		price := pp.GetPrice(asset.Registry.Pair(denoms.BTC, denoms.NUSD))
 		require.False(t, price.Valid)
 		require.Equal(t, types.PriceAbstain, price.Price)
@@ -106,7 +109,7 @@ func TestPriceProvider(t *testing.T) {
 			priceUpdatesC: priceUpdatesC,
 			closeFn:       func() { close(priceUpdatesC) },
 		}
-		pp := newPriceProvider(source, "test", map[asset.Pair]types.Symbol{asset.Registry.Pair(denoms.BTC, denoms.NUSD): "BTC:NUSD"}, zerolog.New(io.Discard))
+		pp := newPriceProvider(source, "test", map[asset.Pair]types.Symbol{asset.Registry.Pair(denoms.BTC, denoms.NUSD): "BTC:NUSD"}, 0, types.StalenessConfig{}, zerolog.New(io.Discard))
 
 		priceUpdatesC <- map[types.Symbol]types.RawPrice{"BTC:NUSD": {Price: 10, UpdateTime: time.Now()}}
 		price := pp.GetPrice(asset.Registry.Pair(denoms.BTC, denoms.NUSD))
@@ -117,13 +120,85 @@ func TestPriceProvider(t *testing.T) {
 		require.Equal(t, "test", price.SourceName)
 	})
 
+	t.Run("counts fetch failures", func(t *testing.T) {
+		priceUpdatesC := make(chan map[types.Symbol]types.RawPrice)
+		source := testAsyncSource{
+			priceUpdatesC: priceUpdatesC,
+			closeFn:       func() { close(priceUpdatesC) },
+		}
+		btc, eth := asset.Registry.Pair(denoms.BTC, denoms.NUSD), asset.Registry.Pair(denoms.ETH, denoms.NUSD)
+		pp := newPriceProvider(source, "test", map[asset.Pair]types.Symbol{btc: "BTC:NUSD", eth: "ETH:NUSD"}, 0, types.StalenessConfig{}, zerolog.New(io.Discard))
+
+		// a symbol missing from the update is not a failed fetch
+		priceUpdatesC <- map[types.Symbol]types.RawPrice{"BTC:NUSD": {Price: 10, UpdateTime: time.Now()}}
+		priceUpdatesC <- map[types.Symbol]types.RawPrice{}
+		priceUpdatesC <- map[types.Symbol]types.RawPrice{}
+		// sending a fourth update ensures the third one was applied
+		priceUpdatesC <- map[types.Symbol]types.RawPrice{}
+
+		fetches, failures := pp.fetchStatus()
+		require.GreaterOrEqual(t, fetches, uint64(3))
+		require.GreaterOrEqual(t, failures, 2)
+
+		priceUpdatesC <- map[types.Symbol]types.RawPrice{"BTC:NUSD": {Price: 10, UpdateTime: time.Now()}}
+		priceUpdatesC <- map[types.Symbol]types.RawPrice{"BTC:NUSD": {Price: 10, UpdateTime: time.Now()}}
+		_, failures = pp.fetchStatus()
+		require.Zero(t, failures)
+	})
+
+	t.Run("returns twap", func(t *testing.T) {
+		priceUpdatesC := make(chan map[types.Symbol]types.RawPrice)
+		source := testAsyncSource{
+			priceUpdatesC: priceUpdatesC,
+			closeFn:       func() { close(priceUpdatesC) },
+		}
+		pair := asset.Registry.Pair(denoms.BTC, denoms.NUSD)
+		pp := newPriceProvider(source, "test", map[asset.Pair]types.Symbol{pair: "BTC:NUSD"}, time.Minute, types.StalenessConfig{}, zerolog.New(io.Discard))
+
+		now := time.Now()
+		priceUpdatesC <- map[types.Symbol]types.RawPrice{"BTC:NUSD": {Price: 10, UpdateTime: now.Add(-30 * time.Second)}}
+		priceUpdatesC <- map[types.Symbol]types.RawPrice{"BTC:NUSD": {Price: 20, UpdateTime: now}}
+		// the third update guarantees the second one was stored
+		priceUpdatesC <- map[types.Symbol]types.RawPrice{}
+		price := pp.GetPrice(pair)
+
+		require.True(t, price.Valid)
+		require.Greater(t, price.Price, float64(10))
+		require.Less(t, price.Price, float64(20))
+	})
+
+	t.Run("per pair max price age", func(t *testing.T) {
+		priceUpdatesC := make(chan map[types.Symbol]types.RawPrice)
+		source := testAsyncSource{
+			priceUpdatesC: priceUpdatesC,
+			closeFn:       func() { close(priceUpdatesC) },
+		}
+		btc, eth := asset.Registry.Pair(denoms.BTC, denoms.NUSD), asset.Registry.Pair(denoms.ETH, denoms.NUSD)
+		staleness := types.StalenessConfig{
+			MaxPriceAge:     types.Duration(time.Minute),
+			PairMaxPriceAge: map[asset.Pair]types.Duration{eth: types.Duration(5 * time.Second)},
+		}
+		pp := newPriceProvider(source, "test", map[asset.Pair]types.Symbol{btc: "BTC:NUSD", eth: "ETH:NUSD"}, 0, staleness, zerolog.New(io.Discard))
+
+		updateTime := time.Now().Add(-30 * time.Second)
+		priceUpdatesC <- map[types.Symbol]types.RawPrice{
+			"BTC:NUSD": {Price: 10, UpdateTime: updateTime},
+			"ETH:NUSD": {Price: 1, UpdateTime: updateTime},
+		}
+		// the second update guarantees the first one was stored
+		priceUpdatesC <- map[types.Symbol]types.RawPrice{}
+
+		require.True(t, pp.GetPrice(btc).Valid)
+		require.False(t, pp.GetPrice(eth).Valid)
+	})
+
 	t.Run("Close assertions", func(t *testing.T) {
 		closed := false
 		pp := newPriceProvider(testAsyncSource{
 			closeFn: func() {
 				closed = true
 			},
-		}, "test", map[asset.Pair]types.Symbol{}, zerolog.New(io.Discard))
+		}, "test", map[asset.Pair]types.Symbol{}, 0, types.StalenessConfig{}, zerolog.New(io.Discard))
 
 		pp.Close()
 		require.True(t, closed)
@@ -135,21 +210,27 @@ func TestIsValid(t *testing.T) {
 		require.True(t, isValid(types.RawPrice{
 			Price:      10,
 			UpdateTime: time.Now(),
-		}, true))
+		}, true, types.PriceTimeout))
 	})
 
 	t.Run("price not found", func(t *testing.T) {
 		require.False(t, isValid(types.RawPrice{
 			Price:      10,
 			UpdateTime: time.Now(),
-		}, false))
+		}, false, types.PriceTimeout))
 	})
 
 	t.Run("price expired", func(t *testing.T) {
 		require.False(t, isValid(types.RawPrice{
 			Price:      20,
 			UpdateTime: time.Now().Add(-1 - 1*types.PriceTimeout),
-		}, true))
+		}, true, types.PriceTimeout))
+	})
+
+	t.Run("custom max price age", func(t *testing.T) {
+		price := types.RawPrice{Price: 20, UpdateTime: time.Now().Add(-time.Minute)}
+		require.True(t, isValid(price, true, 2*time.Minute))
+		require.False(t, isValid(price, true, 30*time.Second))
 	})
 }
 
@@ -181,6 +262,7 @@ func TestAggregatePriceProvider(t *testing.T) {
 				},
 			},
 			map[string]json.RawMessage{},
+			types.DefaultAggregationConfig(),
 			zerolog.New(io.Discard),
 		)
 		defer pp.Close()
@@ -199,10 +281,14 @@ func TestAggregatePriceProvider(t *testing.T) {
 					"susda:usda": sources.Symbol_sUSDaUSDa,
 				},
 				sources.SourceNameUniswapV3: {
-					"usda:usd": sources.Symbol_UniswapV3_USDaUSD,
+					"usda:uusdt": sources.Symbol_UniswapV3_USDaUSD,
+				},
+				sources.SourceNameGateIo: {
+					"uusdt:uusd": "USDT_USD",
 				},
 			},
 			map[string]json.RawMessage{},
+			types.DefaultAggregationConfig(),
 			zerolog.New(io.Discard),
 		)
 		defer pp.Close()
@@ -227,3 +313,356 @@ func TestAggregatePriceProvider(t *testing.T) {
 		assert.Equal(t, sources.SourceNameAvalon, price.SourceName)
 	})
 }
+
+var _ types.PriceProvider = (*testPriceProvider)(nil)
+
+// testPriceProvider is a types.PriceProvider returning fixed prices and volumes.
+type testPriceProvider struct {
+	sourceName string
+	prices     map[asset.Pair]float64
+	volumes    map[asset.Pair]float64
+	fetches    uint64
+	failures   int
+}
+
+func (t *testPriceProvider) GetPrice(pair asset.Pair) types.Price {
+	price, ok := t.prices[pair]
+	if !ok {
+		return types.Price{Pair: pair, Price: types.PriceAbstain, SourceName: t.sourceName, Valid: false}
+	}
+	return types.Price{Pair: pair, Price: price, SourceName: t.sourceName, Volume: t.volumes[pair], Valid: true}
+}
+
+func (t *testPriceProvider) Close() {}
+
+func (t *testPriceProvider) fetchStatus() (uint64, int) {
+	return t.fetches, t.failures
+}
+
+// fetch simulates a successful fetch of the source.
+func (t *testPriceProvider) fetch() {
+	t.fetches++
+	t.failures = 0
+}
+
+// failFetch simulates a fetch of the source that failed as a whole.
+func (t *testPriceProvider) failFetch() {
+	t.fetches++
+	t.failures++
+}
+
+func newTestAggregatePriceProvider(config types.AggregationConfig, providers ...*testPriceProvider) AggregatePriceProvider {
+	providerMap := make(map[string]types.PriceProvider, len(providers))
+	graph := newQuoteGraph()
+	for _, p := range providers {
+		providerMap[p.sourceName] = p
+		for pair := range p.prices {
+			graph.addPair(pair)
+		}
+	}
+	return newAggregatePriceProvider(providerMap, graph, config, zerolog.New(io.Discard))
+}
+
+func TestAggregatePriceProviderStrategies(t *testing.T) {
+	pair := asset.Registry.Pair(denoms.BTC, denoms.USD)
+	providers := []*testPriceProvider{
+		{sourceName: "a", prices: map[asset.Pair]float64{pair: 100}},
+		{sourceName: "b", prices: map[asset.Pair]float64{pair: 101}},
+		{sourceName: "c", prices: map[asset.Pair]float64{pair: 1_000_000}},
+		{sourceName: "d", prices: map[asset.Pair]float64{}},
+	}
+
+	t.Run("median", func(t *testing.T) {
+		pp := newTestAggregatePriceProvider(types.AggregationConfig{Strategy: types.AggregationMedian}, providers...)
+		price := pp.GetPrice(pair)
+		require.True(t, price.Valid)
+		require.Equal(t, float64(101), price.Price)
+		require.Equal(t, []string{"a", "b", "c"}, price.Sources)
+		require.Equal(t, "a,b,c", price.SourceName)
+	})
+
+	t.Run("median of even number of prices", func(t *testing.T) {
+		pp := newTestAggregatePriceProvider(types.AggregationConfig{Strategy: types.AggregationMedian}, providers[:2]...)
+		price := pp.GetPrice(pair)
+		require.True(t, price.Valid)
+		require.Equal(t, 100.5, price.Price)
+	})
+
+	t.Run("first valid", func(t *testing.T) {
+		pp := newTestAggregatePriceProvider(types.AggregationConfig{Strategy: types.AggregationFirstValid}, providers...)
+		price := pp.GetPrice(pair)
+		require.True(t, price.Valid)
+		require.Equal(t, float64(100), price.Price)
+		require.Equal(t, []string{"a"}, price.Sources)
+	})
+
+	t.Run("non-positive prices are ignored", func(t *testing.T) {
+		providers := []*testPriceProvider{
+			{sourceName: "a", prices: map[asset.Pair]float64{pair: 0}},
+			{sourceName: "b", prices: map[asset.Pair]float64{pair: -1}},
+			{sourceName: "c", prices: map[asset.Pair]float64{pair: 100}},
+		}
+		pp := newTestAggregatePriceProvider(types.AggregationConfig{Strategy: types.AggregationMedian}, providers...)
+		price := pp.GetPrice(pair)
+		require.True(t, price.Valid)
+		require.Equal(t, float64(100), price.Price)
+		require.Equal(t, []string{"c"}, price.Sources)
+	})
+
+	t.Run("vwap", func(t *testing.T) {
+		providers := []*testPriceProvider{
+			{sourceName: "a", prices: map[asset.Pair]float64{pair: 100}, volumes: map[asset.Pair]float64{pair: 300}},
+			{sourceName: "b", prices: map[asset.Pair]float64{pair: 104}, volumes: map[asset.Pair]float64{pair: 100}},
+		}
+		pp := newTestAggregatePriceProvider(types.AggregationConfig{Strategy: types.AggregationVWAP}, providers...)
+		price := pp.GetPrice(pair)
+		require.True(t, price.Valid)
+		require.Equal(t, float64(101), price.Price)
+		require.Equal(t, []string{"a", "b"}, price.Sources)
+	})
+
+	t.Run("vwap of inverse pair weighs by quote volume", func(t *testing.T) {
+		inverse := pair.Inverse()
+		providers := []*testPriceProvider{
+			{sourceName: "a", prices: map[asset.Pair]float64{pair: 2}, volumes: map[asset.Pair]float64{pair: 10}},
+			{sourceName: "b", prices: map[asset.Pair]float64{pair: 4}, volumes: map[asset.Pair]float64{pair: 5}},
+		}
+		pp := newTestAggregatePriceProvider(types.AggregationConfig{Strategy: types.AggregationVWAP}, providers...)
+		price := pp.GetPrice(inverse)
+		require.True(t, price.Valid)
+		require.InDelta(t, (0.5*20+0.25*20)/40, price.Price, 1e-12)
+	})
+
+	t.Run("vwap falls back to median without volume", func(t *testing.T) {
+		pp := newTestAggregatePriceProvider(types.AggregationConfig{Strategy: types.AggregationVWAP}, providers...)
+		price := pp.GetPrice(pair)
+		require.True(t, price.Valid)
+		require.Equal(t, float64(101), price.Price)
+	})
+
+	t.Run("no valid price", func(t *testing.T) {
+		pp := newTestAggregatePriceProvider(types.AggregationConfig{Strategy: types.AggregationMedian}, providers[3])
+		price := pp.GetPrice(pair)
+		require.False(t, price.Valid)
+		require.Equal(t, types.PriceAbstain, price.Price)
+		require.Equal(t, "missing", price.SourceName)
+	})
+}
+
+func TestAggregatePriceProviderOutliers(t *testing.T) {
+	pair := asset.Registry.Pair(denoms.BTC, denoms.USD)
+	providers := []*testPriceProvider{
+		{sourceName: "a", prices: map[asset.Pair]float64{pair: 100}},
+		{sourceName: "b", prices: map[asset.Pair]float64{pair: 101}},
+		{sourceName: "c", prices: map[asset.Pair]float64{pair: 102}},
+		{sourceName: "d", prices: map[asset.Pair]float64{pair: 99}},
+		{sourceName: "e", prices: map[asset.Pair]float64{pair: 250}},
+	}
+
+	t.Run("percentage band", func(t *testing.T) {
+		pp := newTestAggregatePriceProvider(types.AggregationConfig{
+			Strategy:               types.AggregationMedian,
+			OutlierMaxDeviationPct: 5,
+		}, providers...)
+		price := pp.GetPrice(pair)
+		require.True(t, price.Valid)
+		require.Equal(t, 100.5, price.Price)
+		require.Equal(t, []string{"a", "b", "c", "d"}, price.Sources)
+	})
+
+	t.Run("standard deviation band", func(t *testing.T) {
+		pp := newTestAggregatePriceProvider(types.AggregationConfig{
+			Strategy:         types.AggregationMedian,
+			OutlierMaxStdDev: 1.5,
+		}, providers...)
+		price := pp.GetPrice(pair)
+		require.True(t, price.Valid)
+		require.Equal(t, []string{"a", "b", "c", "d"}, price.Sources)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		pp := newTestAggregatePriceProvider(types.AggregationConfig{Strategy: types.AggregationMedian}, providers...)
+		price := pp.GetPrice(pair)
+		require.True(t, price.Valid)
+		require.Equal(t, []string{"a", "b", "c", "d", "e"}, price.Sources)
+	})
+
+	t.Run("not enough samples", func(t *testing.T) {
+		pp := newTestAggregatePriceProvider(types.AggregationConfig{
+			Strategy:               types.AggregationMedian,
+			OutlierMaxDeviationPct: 5,
+		}, providers[0], providers[4])
+		price := pp.GetPrice(pair)
+		require.True(t, price.Valid)
+		require.Equal(t, []string{"a", "e"}, price.Sources)
+	})
+}
+
+func TestAggregatePriceProviderMinSources(t *testing.T) {
+	btc := asset.Registry.Pair(denoms.BTC, denoms.USD)
+	stnibi := asset.NewPair("ustnibi", denoms.NIBI)
+	providers := []*testPriceProvider{
+		{sourceName: "a", prices: map[asset.Pair]float64{btc: 100, stnibi: 1.3}},
+		{sourceName: "b", prices: map[asset.Pair]float64{btc: 101}},
+		{sourceName: "c", prices: map[asset.Pair]float64{btc: 250}},
+	}
+
+	for _, strategy := range []types.AggregationStrategy{types.AggregationMedian, types.AggregationFirstValid} {
+		t.Run(string(strategy), func(t *testing.T) {
+			pp := newTestAggregatePriceProvider(types.AggregationConfig{
+				Strategy:   strategy,
+				MinSources: map[asset.Pair]int{btc: 3},
+			}, providers...)
+			require.True(t, pp.GetPrice(btc).Valid)
+			require.True(t, pp.GetPrice(stnibi).Valid, "pairs without min sources need a single source")
+
+			pp = newTestAggregatePriceProvider(types.AggregationConfig{
+				Strategy:   strategy,
+				MinSources: map[asset.Pair]int{btc: 3},
+			}, providers[:2]...)
+			price := pp.GetPrice(btc)
+			require.False(t, price.Valid)
+			require.Equal(t, types.PriceAbstain, price.Price)
+		})
+	}
+
+	t.Run("outliers do not count towards quorum", func(t *testing.T) {
+		pp := newTestAggregatePriceProvider(types.AggregationConfig{
+			Strategy:               types.AggregationMedian,
+			OutlierMaxDeviationPct: 5,
+			MinSources:             map[asset.Pair]int{btc: 3},
+		}, providers...)
+		require.False(t, pp.GetPrice(btc).Valid)
+	})
+}
+
+func TestAggregatePriceProviderDerivedPairs(t *testing.T) {
+	providers := []*testPriceProvider{
+		{sourceName: sources.SourceNameErisProtocol, prices: map[asset.Pair]float64{"ustnibi:unibi": 1.5}},
+		{sourceName: sources.SourceNameGateIo, prices: map[asset.Pair]float64{"unibi:uusd": 0.02, "ueth:uusd": 3000}},
+		{sourceName: sources.SourceNameBybit, prices: map[asset.Pair]float64{"unibi:uusd": 0.02, "ubtc:uusd": 60000}},
+	}
+	config := types.DefaultAggregationConfig()
+	config.DerivedPairs["ueth:ubtc"] = types.DerivedPair{
+		Operation: types.DerivedPairDivide,
+		Pairs:     []asset.Pair{"ueth:uusd", "ubtc:uusd"},
+	}
+	pp := newTestAggregatePriceProvider(config, providers...)
+
+	t.Run("multiply", func(t *testing.T) {
+		price := pp.GetPrice("ustnibi:uusd")
+		require.True(t, price.Valid)
+		require.InDelta(t, 0.03, price.Price, 1e-12)
+		require.Equal(t, sources.SourceNameErisProtocol, price.SourceName)
+		require.Equal(t, []string{sources.SourceNameBybit, sources.SourceNameErisProtocol, sources.SourceNameGateIo}, price.Sources)
+	})
+
+	t.Run("divide", func(t *testing.T) {
+		price := pp.GetPrice("ueth:ubtc")
+		require.True(t, price.Valid)
+		require.InDelta(t, 0.05, price.Price, 1e-12)
+	})
+
+	t.Run("quorum", func(t *testing.T) {
+		config := types.DefaultAggregationConfig()
+		config.MinSources = map[asset.Pair]int{"ustnibi:uusd": 2}
+		pp := newTestAggregatePriceProvider(config, providers...)
+		require.False(t, pp.GetPrice("ustnibi:uusd").Valid, "ustnibi:unibi is only quoted by eris_protocol")
+
+		config.MinSources = map[asset.Pair]int{"ustnibi:uusd": 1}
+		pp = newTestAggregatePriceProvider(config, providers...)
+		require.True(t, pp.GetPrice("ustnibi:uusd").Valid)
+	})
+
+	t.Run("missing operand", func(t *testing.T) {
+		price := pp.GetPrice("susda:usd")
+		require.False(t, price.Valid)
+		require.Equal(t, types.PriceAbstain, price.Price)
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		pp := newTestAggregatePriceProvider(types.DefaultAggregationConfig(),
+			&testPriceProvider{sourceName: sources.SourceNameAvalon, prices: map[asset.Pair]float64{"susda:usda": 1.08}},
+			&testPriceProvider{sourceName: sources.SourceNameUniswapV3, prices: map[asset.Pair]float64{"usda:uusdt": 0.9}},
+			&testPriceProvider{sourceName: sources.SourceNameGateIo, prices: map[asset.Pair]float64{"uusdt:uusd": 1.001}},
+		)
+		// the USDa price is clamped by the default price bound of usda:uusdt
+		price := pp.GetPrice("usda:usd")
+		require.True(t, price.Valid)
+		require.InDelta(t, 0.95*1.001, price.Price, 1e-12)
+		require.Equal(t, sources.SourceNameUniswapV3, price.SourceName)
+
+		price = pp.GetPrice("susda:usd")
+		require.True(t, price.Valid)
+		require.InDelta(t, 1.08*0.95*1.001, price.Price, 1e-12)
+		require.Equal(t, []string{sources.SourceNameAvalon, sources.SourceNameGateIo, sources.SourceNameUniswapV3}, price.Sources)
+	})
+}
+
+func TestAggregatePriceProviderSourceTiers(t *testing.T) {
+	pair := asset.Registry.Pair(denoms.BTC, denoms.USD)
+	providers := []*testPriceProvider{
+		{sourceName: "binance", prices: map[asset.Pair]float64{pair: 100}},
+		{sourceName: "okex", prices: map[asset.Pair]float64{pair: 101}},
+		{sourceName: "bybit", prices: map[asset.Pair]float64{}},
+		{sourceName: "coinmarketcap", prices: map[asset.Pair]float64{pair: 102}},
+		{sourceName: "gateio", prices: map[asset.Pair]float64{pair: 103}},
+	}
+	tiers := [][]string{{"binance", "okex", "bybit"}, {"coinmarketcap"}, {"gateio"}}
+
+	testCases := []struct {
+		name        string
+		minSources  int
+		wantSources []string
+	}{
+		{"first tier meets quorum", 2, []string{"binance", "okex"}},
+		{"falls back to second tier", 3, []string{"binance", "coinmarketcap", "okex"}},
+		{"falls back to every tier", 4, []string{"binance", "coinmarketcap", "gateio", "okex"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := types.DefaultAggregationConfig()
+			config.MinSources = map[asset.Pair]int{pair: tc.minSources}
+			config.SourceTiers = map[asset.Pair][][]string{pair: tiers}
+			pp := newTestAggregatePriceProvider(config, providers...)
+			price := pp.GetPrice(pair)
+			require.True(t, price.Valid)
+			require.Equal(t, tc.wantSources, price.Sources)
+		})
+	}
+
+	t.Run("sources outside of the tiers are not used", func(t *testing.T) {
+		config := types.DefaultAggregationConfig()
+		config.MinSources = map[asset.Pair]int{pair: 5}
+		config.SourceTiers = map[asset.Pair][][]string{pair: tiers[:2]}
+		pp := newTestAggregatePriceProvider(config, providers...)
+		require.False(t, pp.GetPrice(pair).Valid)
+	})
+
+	t.Run("first valid follows tier order", func(t *testing.T) {
+		config := types.DefaultAggregationConfig()
+		config.Strategy = types.AggregationFirstValid
+		config.SourceTiers = map[asset.Pair][][]string{pair: {{"bybit", "okex", "binance"}}}
+		pp := newTestAggregatePriceProvider(config, providers...)
+		price := pp.GetPrice(pair)
+		require.True(t, price.Valid)
+		require.Equal(t, []string{"okex"}, price.Sources)
+	})
+
+	t.Run("tiers apply to routed sources", func(t *testing.T) {
+		config := types.DefaultAggregationConfig()
+		config.MinSources = map[asset.Pair]int{pair: 3}
+		config.SourceTiers = map[asset.Pair][][]string{pair: {{"coinbase", "kraken", "bitfinex"}, {"okex", "bybit"}, {"gateio"}}}
+		pp := newTestAggregatePriceProvider(config,
+			&testPriceProvider{sourceName: "bitfinex", prices: map[asset.Pair]float64{pair: 100, "uusdt:uusd": 1}},
+			&testPriceProvider{sourceName: "bybit", prices: map[asset.Pair]float64{"ubtc:uusdt": 102}},
+			&testPriceProvider{sourceName: "coinbase", prices: map[asset.Pair]float64{pair: 101}},
+			&testPriceProvider{sourceName: "gateio", prices: map[asset.Pair]float64{"ubtc:uusdt": 103}},
+			&testPriceProvider{sourceName: "okex", prices: map[asset.Pair]float64{"ubtc:uusdt": 104}},
+		)
+		price := pp.GetPrice(pair)
+		require.True(t, price.Valid)
+		require.Equal(t, []string{"bitfinex", "bybit", "coinbase", "okex"}, price.Sources)
+		require.Equal(t, 4, price.Depth)
+	})
+}
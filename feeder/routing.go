@@ -0,0 +1,300 @@
+package feeder
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/NibiruChain/nibiru/v2/x/common/asset"
+	"github.com/NibiruChain/nibiru/v2/x/common/denoms"
+	"github.com/NibiruChain/nibiru/v2/x/common/set"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/NibiruChain/pricefeeder/metrics"
+	"github.com/NibiruChain/pricefeeder/types"
+)
+
+// maxRouteHops is the maximum number of pairs chained together to compute the
+// price of a pair that is not quoted directly, e.g. ubtc:uusdt * uusdt:uusd.
+const maxRouteHops = 3
+
+// quoteGraph is an undirected graph whose nodes are denoms and whose edges
+// are the pairs quoted by at least one source. A pair can be priced in both
+// directions, since the inverse price is the reciprocal.
+type quoteGraph struct {
+	pairs     set.Set[asset.Pair]
+	neighbors map[string]set.Set[string]
+}
+
+func newQuoteGraph() quoteGraph {
+	return quoteGraph{
+		pairs:     set.New[asset.Pair](),
+		neighbors: map[string]set.Set[string]{},
+	}
+}
+
+// addPair adds a pair quoted by a source to the graph.
+func (g quoteGraph) addPair(pair asset.Pair) {
+	g.pairs.Add(pair)
+	base, quote := pair.BaseDenom(), pair.QuoteDenom()
+	if _, ok := g.neighbors[base]; !ok {
+		g.neighbors[base] = set.New[string]()
+	}
+	if _, ok := g.neighbors[quote]; !ok {
+		g.neighbors[quote] = set.New[string]()
+	}
+	g.neighbors[base].Add(quote)
+	g.neighbors[quote].Add(base)
+}
+
+// quotes reports whether at least one source quotes the pair as is.
+func (g quoteGraph) quotes(pair asset.Pair) bool {
+	return g.pairs.Has(pair)
+}
+
+// route is a chain of pairs whose prices multiply to the price of the routed
+// pair, e.g. [ubtc:uusdt, uusdt:uusd] for ubtc:uusd.
+type route []asset.Pair
+
+func (r route) String() string {
+	legs := make([]string, len(r))
+	for i, leg := range r {
+		legs[i] = leg.String()
+	}
+	return strings.Join(legs, "*")
+}
+
+// routes returns every route of at most maxHops pairs from the base to the
+// quote denom of the pair, ordered by number of hops and then by name.
+func (g quoteGraph) routes(pair asset.Pair, maxHops int) []route {
+	var (
+		routes  []route
+		visited = set.New[string]()
+		walk    func(denom string, path route)
+	)
+	target := pair.QuoteDenom()
+	walk = func(denom string, path route) {
+		if denom == target {
+			routes = append(routes, append(route{}, path...))
+			return
+		}
+		if len(path) == maxHops {
+			return
+		}
+		visited.Add(denom)
+		defer visited.Remove(denom)
+		for next := range g.neighbors[denom] {
+			if visited.Has(next) {
+				continue
+			}
+			walk(next, append(path, asset.NewPair(denom, next)))
+		}
+	}
+	walk(pair.BaseDenom(), nil)
+
+	sort.Slice(routes, func(i, j int) bool {
+		if len(routes[i]) != len(routes[j]) {
+			return len(routes[i]) < len(routes[j])
+		}
+		return routes[i].String() < routes[j].String()
+	})
+	return routes
+}
+
+var routedPrices = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: metrics.PrometheusNamespace,
+	Name:      "routed_prices_total",
+	Help:      "The total number of prices provided by the aggregate price provider, by pair and each route of quoted pairs used to compute it",
+}, []string{"pair", "route"})
+
+var depeggedRoutes = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: metrics.PrometheusNamespace,
+	Name:      "depegged_routes_total",
+	Help:      "The total number of routes rejected because a stablecoin they convert through is de-pegged from USD, by pair and stablecoin pair",
+}, []string{"pair", "stablecoin_pair"})
+
+// isDepegged reports whether the leg converts between a stablecoin and USD at
+// a price further away from 1 than the configured de-peg threshold.
+func (a AggregatePriceProvider) isDepegged(leg asset.Pair, price float64) bool {
+	threshold := a.config.StablecoinDepegThresholdPct
+	if threshold <= 0 {
+		return false
+	}
+	base, quote := leg.BaseDenom(), leg.QuoteDenom()
+	isStablecoinLeg := (a.config.IsStablecoin(base) && quote == denoms.USD) ||
+		(base == denoms.USD && a.config.IsStablecoin(quote))
+	return isStablecoinLeg && math.Abs(price-1)*100 > threshold
+}
+
+// routeConversion converts the prices of the first pair of a route to the
+// routed pair.
+type routeConversion struct {
+	route route
+	// factor is the product of the aggregated prices of the other pairs of
+	// the route.
+	factor float64
+	// sources are the sources of the other pairs of the route.
+	sources []string
+}
+
+// routePrice computes the price of the pair from every route through the
+// quote graph. The source prices of the first pair of each route are
+// converted to the pair and combined, and the quorum of the pair is checked
+// against their sources.
+func (a AggregatePriceProvider) routePrice(pair asset.Pair) types.Price {
+	legPrices := map[asset.Pair][]types.Price{}
+	var conversions []routeConversion
+	for _, r := range a.graph.routes(pair, maxRouteHops) {
+		if conversion, ok := a.convertRoute(pair, r, legPrices); ok {
+			conversions = append(conversions, conversion)
+		}
+	}
+	prices, priceRoutes := a.routedSourcePrices(pair, conversions)
+	if len(prices) == 0 {
+		return a.missingPrice(pair)
+	}
+	if minSources := a.config.MinSourcesFor(pair); len(prices) < minSources {
+		a.logger.Debug().
+			Str("pair", pair.String()).
+			Int("sources", len(prices)).
+			Int("min_sources", minSources).
+			Msg("not enough sources to reach quorum")
+		return a.missingPrice(pair)
+	}
+
+	price, baseSources := a.combine(prices)
+	sources := set.New[string]()
+	usedRoutes := set.New[string]()
+	for _, sourceName := range baseSources {
+		conversion := priceRoutes[sourceName]
+		sources.Add(sourceName)
+		sources.AddMulti(conversion.sources...)
+		usedRoutes.Add(conversion.route.String())
+	}
+	sourceNames := sources.ToSlice()
+	sort.Strings(sourceNames)
+	routeNames := usedRoutes.ToSlice()
+	sort.Strings(routeNames)
+	for _, sourceName := range sourceNames {
+		aggregatePriceProvider.WithLabelValues(pair.String(), sourceName, "true").Inc()
+	}
+	for _, routeName := range routeNames {
+		routedPrices.WithLabelValues(pair.String(), routeName).Inc()
+	}
+	a.logger.Debug().
+		Str("pair", pair.String()).
+		Strs("routes", routeNames).
+		Strs("sources", sourceNames).
+		Float64("price", price).
+		Msg("aggregated price")
+
+	return types.Price{
+		Pair:       pair,
+		Price:      price,
+		SourceName: strings.Join(sourceNames, ","),
+		Sources:    sourceNames,
+		Depth:      len(baseSources),
+		Valid:      true,
+	}
+}
+
+// routedSourcePrices returns the valid prices of the pair, at most one per
+// source and without outliers, along with the route each of them was
+// converted along. A source quoting the pair along several routes uses the
+// one with the fewest hops, so the direct pair takes precedence.
+//
+// Sources are queried tier by tier, in the order configured for the pair, and
+// lower tiers are only used while the higher ones do not reach the quorum of
+// the pair. The tiers of the pair apply to the first pair of each of its
+// routes, e.g. to the sources quoting ubtc:uusdt for ubtc:uusd.
+func (a AggregatePriceProvider) routedSourcePrices(
+	pair asset.Pair,
+	conversions []routeConversion,
+) ([]types.Price, map[string]routeConversion) {
+	tiers := a.tiers(pair)
+	minSources := a.config.MinSourcesFor(pair)
+
+	var validPrices []types.Price
+	priceRoutes := map[string]routeConversion{}
+	for i, tier := range tiers {
+		for _, sourceName := range tier {
+			p, ok := a.providers[sourceName]
+			if !ok {
+				continue
+			}
+			for _, conversion := range conversions {
+				price, ok := a.sourcePrice(p, conversion.route[0])
+				if !ok {
+					continue
+				}
+				if _, seen := priceRoutes[price.SourceName]; seen {
+					break
+				}
+				price.Pair, price.Price = pair, price.Price*conversion.factor
+				priceRoutes[price.SourceName] = conversion
+				validPrices = append(validPrices, price)
+				break
+			}
+		}
+
+		if len(a.filterOutliers(validPrices)) >= minSources {
+			break
+		}
+		if i < len(tiers)-1 {
+			a.logger.Debug().
+				Str("pair", pair.String()).
+				Int("tier", i+1).
+				Int("sources", len(validPrices)).
+				Int("min_sources", minSources).
+				Msg("source tier below quorum, falling back to next tier")
+		}
+	}
+
+	return a.rejectOutliers(pair, validPrices), priceRoutes
+}
+
+// convertRoute computes the conversion from the first pair of the route to
+// the routed pair, from the aggregated prices of the other pairs. It returns
+// false if any other pair does not reach its own source quorum, or if the
+// route converts through a de-pegged stablecoin. Source prices are memoized in
+// legPrices, since the same pair is usually shared by several routes.
+func (a AggregatePriceProvider) convertRoute(
+	pair asset.Pair,
+	r route,
+	legPrices map[asset.Pair][]types.Price,
+) (routeConversion, bool) {
+	conversion := routeConversion{route: r, factor: 1}
+	for _, leg := range r[1:] {
+		prices, ok := legPrices[leg]
+		if !ok {
+			prices = a.sourcePrices(leg)
+			legPrices[leg] = prices
+		}
+		if len(prices) == 0 || len(prices) < a.config.MinSourcesFor(leg) {
+			a.logger.Debug().
+				Str("pair", pair.String()).
+				Str("route", r.String()).
+				Str("leg", leg.String()).
+				Int("sources", len(prices)).
+				Msg("not enough sources for route")
+			return routeConversion{}, false
+		}
+
+		legPrice, sourceNames := a.combine(prices)
+		if a.isDepegged(leg, legPrice) {
+			a.logger.Warn().
+				Str("pair", pair.String()).
+				Str("route", r.String()).
+				Str("stablecoin_pair", leg.String()).
+				Float64("price", legPrice).
+				Float64("threshold_pct", a.config.StablecoinDepegThresholdPct).
+				Msg("stablecoin de-pegged, rejecting route")
+			depeggedRoutes.WithLabelValues(pair.String(), leg.String()).Inc()
+			return routeConversion{}, false
+		}
+		conversion.factor *= legPrice
+		conversion.sources = append(conversion.sources, sourceNames...)
+	}
+	return conversion, true
+}
@@ -0,0 +1,150 @@
+package feeder
+
+import (
+	"testing"
+
+	"github.com/NibiruChain/nibiru/v2/x/common/asset"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NibiruChain/pricefeeder/types"
+)
+
+func TestQuoteGraphRoutes(t *testing.T) {
+	graph := newQuoteGraph()
+	for _, pair := range []asset.Pair{"ubtc:uusd", "ubtc:uusdt", "uusdt:uusd", "uusdc:uusdt", "uusdc:uusd", "unibi:uusdt"} {
+		graph.addPair(pair)
+	}
+
+	require.Equal(t, []route{
+		{"ubtc:uusd"},
+		{"ubtc:uusdt", "uusdt:uusd"},
+		{"ubtc:uusdt", "uusdt:uusdc", "uusdc:uusd"},
+	}, graph.routes("ubtc:uusd", maxRouteHops))
+
+	require.Equal(t, []route{
+		{"unibi:uusdt", "uusdt:uusd"},
+	}, graph.routes("unibi:uusd", 2))
+
+	require.Equal(t, []route{
+		{"uusd:uusdt"},
+		{"uusd:ubtc", "ubtc:uusdt"},
+		{"uusd:uusdc", "uusdc:uusdt"},
+	}, graph.routes("uusd:uusdt", maxRouteHops), "pairs are routable in the inverse direction")
+
+	require.Empty(t, graph.routes("ueth:uusd", maxRouteHops))
+}
+
+func TestAggregatePriceProviderRouting(t *testing.T) {
+	providers := []*testPriceProvider{
+		{sourceName: "bitfinex", prices: map[asset.Pair]float64{"ubtc:uusd": 60_000, "uusdt:uusd": 0.99}},
+		{sourceName: "gateio", prices: map[asset.Pair]float64{"ubtc:uusdt": 61_000, "uusdt:uusd": 1.01, "unibi:uusdt": 0.02}},
+		{sourceName: "okex", prices: map[asset.Pair]float64{"ubtc:uusdt": 60_500, "uusdt:uusdc": 1}},
+		{sourceName: "bybit", prices: map[asset.Pair]float64{"ubtc:uusdt": 60_600, "unibi:uusdt": 0.03}},
+	}
+
+	t.Run("direct and routed prices are combined", func(t *testing.T) {
+		pp := newTestAggregatePriceProvider(types.DefaultAggregationConfig(), providers...)
+		price := pp.GetPrice("ubtc:uusd")
+		require.True(t, price.Valid)
+		// median(60000, 61000 * 1, 60500 * 1, 60600 * 1), with uusdt:uusd at median(0.99, 1.01)
+		require.InDelta(t, 60_550.0, price.Price, 1e-6)
+		require.Equal(t, []string{"bitfinex", "bybit", "gateio", "okex"}, price.Sources)
+		require.Equal(t, 4, price.Depth)
+	})
+
+	t.Run("direct pair is preferred for a source quoting both", func(t *testing.T) {
+		pp := newTestAggregatePriceProvider(types.DefaultAggregationConfig(),
+			&testPriceProvider{sourceName: "bitfinex", prices: map[asset.Pair]float64{"ubtc:uusd": 60_000, "ubtc:uusdt": 61_000, "uusdt:uusd": 0.99}},
+		)
+		price := pp.GetPrice("ubtc:uusd")
+		require.True(t, price.Valid)
+		require.Equal(t, 60_000.0, price.Price)
+		require.Equal(t, 1, price.Depth)
+	})
+
+	t.Run("routed pair not quoted directly", func(t *testing.T) {
+		pp := newTestAggregatePriceProvider(types.DefaultAggregationConfig(), providers...)
+		price := pp.GetPrice("unibi:uusd")
+		require.True(t, price.Valid)
+		require.InDelta(t, 0.025, price.Price, 1e-12)
+		require.Equal(t, 2, price.Depth)
+	})
+
+	t.Run("inverse quotes contribute to a pair", func(t *testing.T) {
+		pp := newTestAggregatePriceProvider(types.DefaultAggregationConfig(), providers...)
+		price := pp.GetPrice("uusdc:uusdt")
+		require.True(t, price.Valid)
+		require.Equal(t, 1.0, price.Price)
+		require.Equal(t, []string{"okex"}, price.Sources)
+	})
+
+	t.Run("route quorum", func(t *testing.T) {
+		config := types.DefaultAggregationConfig()
+		config.MinSources = map[asset.Pair]int{"unibi:uusd": 2}
+		pp := newTestAggregatePriceProvider(config, providers...)
+		require.True(t, pp.GetPrice("unibi:uusd").Valid)
+
+		config.MinSources = map[asset.Pair]int{"unibi:uusd": 3}
+		pp = newTestAggregatePriceProvider(config, providers...)
+		require.False(t, pp.GetPrice("unibi:uusd").Valid)
+	})
+
+	t.Run("quorum counts the sources of every route", func(t *testing.T) {
+		config := types.DefaultAggregationConfig()
+		config.MinSources = map[asset.Pair]int{"ubtc:uusd": 3}
+		pp := newTestAggregatePriceProvider(config,
+			&testPriceProvider{sourceName: "binance", prices: map[asset.Pair]float64{"ubtc:uusdt": 60_100}},
+			&testPriceProvider{sourceName: "bitfinex", prices: map[asset.Pair]float64{"ubtc:uusd": 60_000, "uusdt:uusd": 1}},
+			&testPriceProvider{sourceName: "bybit", prices: map[asset.Pair]float64{"ubtc:uusdt": 60_200}},
+			&testPriceProvider{sourceName: "coinbase", prices: map[asset.Pair]float64{"ubtc:uusd": 60_050}},
+			&testPriceProvider{sourceName: "okex", prices: map[asset.Pair]float64{"ubtc:uusdt": 60_150}},
+		)
+		price := pp.GetPrice("ubtc:uusd")
+		require.True(t, price.Valid)
+		require.Equal(t, 5, price.Depth)
+		require.InDelta(t, 60_100.0, price.Price, 1e-6)
+	})
+}
+
+func TestAggregatePriceProviderStablecoinDepeg(t *testing.T) {
+	providers := []*testPriceProvider{
+		{sourceName: "gateio", prices: map[asset.Pair]float64{"ubtc:uusdt": 61_000, "uusdt:uusd": 0.9, "unibi:uusdt": 0.02}},
+		{sourceName: "bitfinex", prices: map[asset.Pair]float64{"ubtc:uusd": 55_000, "uusdt:uusd": 0.9}},
+		{sourceName: "bybit", prices: map[asset.Pair]float64{"ubtc:uusdt": 61_000}},
+	}
+	pp := newTestAggregatePriceProvider(types.DefaultAggregationConfig(), providers...)
+
+	t.Run("routes through a de-pegged stablecoin are rejected", func(t *testing.T) {
+		price := pp.GetPrice("ubtc:uusd")
+		require.True(t, price.Valid)
+		require.Equal(t, 55_000.0, price.Price)
+		require.Equal(t, []string{"bitfinex"}, price.Sources)
+	})
+
+	t.Run("pair abstains without another route", func(t *testing.T) {
+		pp := newTestAggregatePriceProvider(types.DefaultAggregationConfig(), providers[0])
+		price := pp.GetPrice("unibi:uusd")
+		require.False(t, price.Valid)
+		require.Equal(t, types.PriceAbstain, price.Price)
+	})
+
+	t.Run("stablecoin pair itself is still priced", func(t *testing.T) {
+		price := pp.GetPrice("uusdt:uusd")
+		require.True(t, price.Valid)
+		require.Equal(t, 0.9, price.Price)
+	})
+
+	t.Run("pair abstains when the stablecoin price is unavailable", func(t *testing.T) {
+		pp := newTestAggregatePriceProvider(types.DefaultAggregationConfig(), providers[2])
+		require.False(t, pp.GetPrice("ubtc:uusd").Valid)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		config := types.DefaultAggregationConfig()
+		config.StablecoinDepegThresholdPct = 0
+		pp := newTestAggregatePriceProvider(config, providers[0])
+		price := pp.GetPrice("unibi:uusd")
+		require.True(t, price.Valid)
+		require.InDelta(t, 0.018, price.Price, 1e-12)
+	})
+}
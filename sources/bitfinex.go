@@ -17,7 +17,7 @@ const (
 	SourceNameBitfinex = "bitfinex"
 )
 
-var _ types.FetchPricesFunc = BitfinexPriceUpdate
+var _ types.FetchRawPricesFunc = BitfinexPriceUpdate
 
 func BitfinexSymbolCsv(symbols set.Set[types.Symbol]) string {
 	s := ""
@@ -27,11 +27,12 @@ func BitfinexSymbolCsv(symbols set.Set[types.Symbol]) string {
 	return s[:len(s)-1]
 }
 
-// BitfinexPriceUpdate returns the prices given the symbols or an error.
-func BitfinexPriceUpdate(symbols set.Set[types.Symbol], logger zerolog.Logger) (rawPrices map[types.Symbol]float64, err error) {
+// BitfinexPriceUpdate returns the prices and 24h volumes given the symbols or an error.
+func BitfinexPriceUpdate(symbols set.Set[types.Symbol], logger zerolog.Logger) (rawPrices map[types.Symbol]types.RawPrice, err error) {
 	type ticker []any
 	const size = 11
 	const lastPriceIndex = 7
+	const volumeIndex = 8
 	const symbolNameIndex = 0
 
 	url := "https://api-pub.bitfinex.com/v2/tickers?symbols=" + BitfinexSymbolCsv(symbols)
@@ -64,15 +65,16 @@ func BitfinexPriceUpdate(symbols set.Set[types.Symbol], logger zerolog.Logger) (
 		return nil, err
 	}
 
-	rawPrices = make(map[types.Symbol]float64)
+	rawPrices = make(map[types.Symbol]types.RawPrice)
 	for _, ticker := range tickers {
 		if len(ticker) != size {
 			return nil, fmt.Errorf("impossible to parse ticker size %d, %#v", len(ticker), ticker) // TODO(mercilex): return or log and continue?
 		}
 		symbol := types.Symbol(ticker[symbolNameIndex].(string))
 		lastPrice := ticker[lastPriceIndex].(float64)
+		volume, _ := ticker[volumeIndex].(float64)
 
-		rawPrices[symbol] = lastPrice
+		rawPrices[symbol] = types.RawPrice{Price: lastPrice, Volume: volume}
 		logger.Debug().Msg(fmt.Sprintf("fetched price for %s on data source %s: %f", symbol, SourceNameBitfinex, lastPrice))
 	}
 
@@ -16,7 +16,9 @@ func TestBitfinexSource(t *testing.T) {
 		rawPrices, err := BitfinexPriceUpdate(set.New[types.Symbol]("tBTCUSD", "tETHUSD"), zerolog.New(io.Discard))
 		require.NoError(t, err)
 		require.Equal(t, 2, len(rawPrices))
-		require.NotZero(t, rawPrices["tBTCUSD"])
-		require.NotZero(t, rawPrices["tETHUSD"])
+		require.NotZero(t, rawPrices["tBTCUSD"].Price)
+		require.NotZero(t, rawPrices["tBTCUSD"].Volume)
+		require.NotZero(t, rawPrices["tETHUSD"].Price)
+		require.NotZero(t, rawPrices["tETHUSD"].Volume)
 	})
 }
@@ -19,22 +19,23 @@ const (
 	SourceNameBybit = "bybit"
 )
 
-var _ types.FetchPricesFunc = BybitPriceUpdate
+var _ types.FetchRawPricesFunc = BybitPriceUpdate
 
 type BybitResponse struct {
 	Data struct {
 		List []struct {
 			Symbol string `json:"symbol"`
 			Price  string `json:"lastPrice"`
+			Volume string `json:"volume24h"`
 		} `json:"list"`
 	} `json:"result"`
 }
 
 const ErrBybitBlockAccess = "configured to block access from your country"
 
-// BybitPriceUpdate returns the prices for given symbols or an error.
+// BybitPriceUpdate returns the prices and 24h volumes for given symbols or an error.
 // Uses BYBIT API at https://bybit-exchange.github.io/docs/v5/market/tickers.
-func BybitPriceUpdate(symbols set.Set[types.Symbol], logger zerolog.Logger) (rawPrices map[types.Symbol]float64, err error) {
+func BybitPriceUpdate(symbols set.Set[types.Symbol], logger zerolog.Logger) (rawPrices map[types.Symbol]types.RawPrice, err error) {
 	url := "https://api.bybit.com/v5/market/tickers?category=spot"
 
 	resp, err := http.Get(url)
@@ -70,7 +71,7 @@ func BybitPriceUpdate(symbols set.Set[types.Symbol], logger zerolog.Logger) (raw
 		return nil, err
 	}
 
-	rawPrices = make(map[types.Symbol]float64)
+	rawPrices = make(map[types.Symbol]types.RawPrice)
 
 	for _, ticker := range response.Data.List {
 		symbol := types.Symbol(ticker.Symbol)
@@ -79,9 +80,14 @@ func BybitPriceUpdate(symbols set.Set[types.Symbol], logger zerolog.Logger) (raw
 			logger.Err(err).Msgf("failed to parse price for %s on data source %s", symbol, SourceNameBybit)
 			continue
 		}
+		volume, err := strconv.ParseFloat(ticker.Volume, 64)
+		if err != nil {
+			logger.Err(err).Msgf("failed to parse volume for %s on data source %s", symbol, SourceNameBybit)
+			volume = 0
+		}
 
 		if _, ok := symbols[symbol]; ok {
-			rawPrices[symbol] = price
+			rawPrices[symbol] = types.RawPrice{Price: price, Volume: volume}
 		}
 	}
 	logger.Debug().Msgf("fetched prices for %s on data source %s: %v", symbols, SourceNameBybit, rawPrices)
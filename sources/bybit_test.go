@@ -20,7 +20,9 @@ func TestBybitPriceUpdate(t *testing.T) {
 		}
 		require.NoError(t, err)
 		require.Equal(t, 2, len(rawPrices))
-		require.NotZero(t, rawPrices["BTCUSDT"])
-		require.NotZero(t, rawPrices["ETHUSDT"])
+		require.NotZero(t, rawPrices["BTCUSDT"].Price)
+		require.NotZero(t, rawPrices["BTCUSDT"].Volume)
+		require.NotZero(t, rawPrices["ETHUSDT"].Price)
+		require.NotZero(t, rawPrices["ETHUSDT"].Volume)
 	})
 }
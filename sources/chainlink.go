@@ -2,210 +2,183 @@ package sources
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"math/big"
 	"time"
 
 	"github.com/NibiruChain/nibiru/v2/x/common/set"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/rs/zerolog"
 
+	"github.com/NibiruChain/pricefeeder/metrics"
 	"github.com/NibiruChain/pricefeeder/types"
 	"github.com/NibiruChain/pricefeeder/types/chainlink"
 )
 
-var _ types.FetchPricesFunc = ChainlinkPriceUpdate
-
 const (
 	SourceNameChainLink = "chainlink"
+	// chainlinkMaxPriceAge is the default max price age of the chainlink
+	// source, whose prices are as old as the last round of their feed. It
+	// covers the 24h heartbeat of the slowest feeds.
+	chainlinkMaxPriceAge = 25 * time.Hour
 )
 
-// ChainType represents different blockchain networks
+// ChainType represents different blockchain networks, named as in
+// types.DefaultNetworkConfigs or EVM_NETWORKS.
 type ChainType string
 
 const (
-	ChainB2 ChainType = "b2"
+	ChainB2       ChainType = "b2"
+	ChainEthereum ChainType = "ethereum"
 )
 
 // ChainlinkConfig represents configuration for a specific Chainlink oracle
 type ChainlinkConfig struct {
-	Chain           ChainType
-	ContractAddress common.Address
-	Description     string        // Expected description (for sanity check)
-	MaxDataAge      time.Duration // Maximum acceptable data age
+	Chain           ChainType      `json:"network"`
+	ContractAddress common.Address `json:"address"`
+	Description     string         `json:"description"`  // Expected description (for sanity check)
+	MaxDataAge      types.Duration `json:"max_data_age"` // Maximum acceptable data age, rejecting older answers
+}
+
+// ChainlinkSourceConfig is the configuration of the chainlink source, mapping
+// symbols to the oracles they are read from. Configured feeds are added to, or
+// replace, the default ones.
+type ChainlinkSourceConfig struct {
+	Feeds map[types.Symbol]ChainlinkConfig `json:"feeds"`
 }
 
-// chainlinkConfigMap maps trading pair symbols to their Chainlink oracle configurations
-var chainlinkConfigMap = map[types.Symbol]ChainlinkConfig{
+// defaultChainlinkFeeds maps trading pair symbols to their default Chainlink oracle configurations
+var defaultChainlinkFeeds = map[types.Symbol]ChainlinkConfig{
 	"uBTC/BTC": {
 		Chain:           ChainB2,
 		ContractAddress: common.HexToAddress("0xA2ed2B84073B3BA4F3Bd6528260d85EdDFD72fF2"),
 		Description:     "uBTC/BTC Exchange Rate",
-		MaxDataAge:      0, // No age limit for this example
+		MaxDataAge:      types.Duration(25 * time.Hour), // 24h heartbeat, plus an hour of margin
 	},
 }
 
-// chainConnectors maps chain types to their connection functions
-var chainConnectors = map[ChainType]func(time.Duration, zerolog.Logger) (*ethclient.Client, error){
-	ChainB2: types.ConnectToB2,
-}
-
-// ChainlinkPriceUpdate retrieves exchange rates from various Chainlink oracles across different chains
-func ChainlinkPriceUpdate(symbols set.Set[types.Symbol], logger zerolog.Logger) (map[types.Symbol]float64, error) {
-	timeout := 10 * time.Second
-	prices := make(map[types.Symbol]float64)
-
-	// Group symbols by chain to optimize connections
-	symbolsByChain := groupSymbolsByChain(symbols, logger)
-
-	// Process each chain separately
-	for chain, chainSymbols := range symbolsByChain {
-		chainPrices, err := fetchPricesFromChain(chain, chainSymbols, timeout, logger)
-		if err != nil {
-			return nil, fmt.Errorf("failed to fetch prices from %s: %w", chain, err)
-		}
-
-		// Merge results
-		for symbol, price := range chainPrices {
-			prices[symbol] = price
+// ParseChainlinkConfig parses and validates the configuration of the chainlink
+// source, returning the default feeds merged with the configured ones.
+func ParseChainlinkConfig(jsonConfig json.RawMessage) (map[types.Symbol]ChainlinkConfig, error) {
+	var config ChainlinkSourceConfig
+	if len(jsonConfig) > 0 {
+		if err := json.Unmarshal(jsonConfig, &config); err != nil {
+			return nil, fmt.Errorf("invalid chainlink config: %w", err)
 		}
 	}
 
-	if len(prices) == 0 {
-		return nil, fmt.Errorf("no prices were successfully fetched")
+	feeds := make(map[types.Symbol]ChainlinkConfig, len(defaultChainlinkFeeds)+len(config.Feeds))
+	for symbol, feed := range defaultChainlinkFeeds {
+		feeds[symbol] = feed
 	}
-
-	return prices, nil
-}
-
-// groupSymbolsByChain organizes symbols by their target blockchain
-func groupSymbolsByChain(symbols set.Set[types.Symbol], logger zerolog.Logger) map[ChainType][]types.Symbol {
-	symbolsByChain := make(map[ChainType][]types.Symbol)
-
-	for _, symbol := range symbols.ToSlice() {
-		config, ok := chainlinkConfigMap[symbol]
-		if !ok {
-			logger.Warn().
-				Str("symbol", string(symbol)).
-				Msg("unsupported symbol for Chainlink oracle")
-			continue
+	for symbol, feed := range config.Feeds {
+		if _, err := types.GetRPCEndpoints(string(feed.Chain)); err != nil {
+			return nil, fmt.Errorf("invalid chainlink config for %s: %w", symbol, err)
 		}
-
-		symbolsByChain[config.Chain] = append(symbolsByChain[config.Chain], symbol)
+		if feed.ContractAddress == (common.Address{}) {
+			return nil, fmt.Errorf("invalid chainlink config for %s: missing aggregator address", symbol)
+		}
+		if feed.MaxDataAge < 0 {
+			return nil, fmt.Errorf("invalid chainlink config for %s: max data age must not be negative", symbol)
+		}
+		feeds[symbol] = feed
 	}
-
-	return symbolsByChain
+	return feeds, nil
 }
 
-// fetchPricesFromChain fetches prices for all symbols on a specific chain
-func fetchPricesFromChain(
-	chain ChainType,
-	symbols []types.Symbol,
-	timeout time.Duration,
-	logger zerolog.Logger,
-) (map[types.Symbol]float64, error) {
-	// Get the appropriate connector function
-	connectFunc, ok := chainConnectors[chain]
-	if !ok {
-		return nil, fmt.Errorf("unsupported chain: %s", chain)
-	}
-
-	// Connect to the chain
-	client, err := connectFunc(timeout, logger)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to %s: %w", chain, err)
-	}
-	defer client.Close()
-
-	ctx := context.Background()
-	prices := make(map[types.Symbol]float64)
+// ChainlinkPriceUpdate retrieves exchange rates from the Chainlink oracles
+// configured for the symbols, connecting once to each of their networks.
+// Prices are reported with the update time of their round.
+func ChainlinkPriceUpdate(chainlinkConfig json.RawMessage) types.FetchRawPricesFunc {
+	return chainlinkPriceUpdate(chainlinkConfig, connectToEVMNetwork)
+}
 
-	// Fetch price for each symbol on this chain
-	for _, symbol := range symbols {
-		config := chainlinkConfigMap[symbol] // We know it exists from grouping
-		price, err := fetchPriceFromOracle(ctx, client, symbol, config, logger)
+func chainlinkPriceUpdate(chainlinkConfig json.RawMessage, connect evmConnectFunc) types.FetchRawPricesFunc {
+	return func(symbols set.Set[types.Symbol], logger zerolog.Logger) (map[types.Symbol]types.RawPrice, error) {
+		feeds, err := ParseChainlinkConfig(chainlinkConfig)
 		if err != nil {
-			return nil, fmt.Errorf("failed to fetch price for %s on %s: %w", symbol, chain, err)
+			logger.Err(err).Msg("failed to extract chainlink config")
+			metrics.PriceSourceCounter.WithLabelValues(SourceNameChainLink, "false").Inc()
+			return nil, err
 		}
 
-		prices[symbol] = price
+		networks := make(map[types.Symbol]string, len(feeds))
+		for symbol, feed := range feeds {
+			networks[symbol] = string(feed.Chain)
+		}
+		return fetchEVMRawPrices(SourceNameChainLink, symbols, networks, connect,
+			func(ctx context.Context, caller bind.ContractCaller, symbol types.Symbol) (types.RawPrice, error) {
+				return fetchPriceFromOracle(ctx, caller, symbol, feeds[symbol])
+			}, logger)
 	}
-	return prices, nil
 }
 
-// fetchPriceFromOracle fetches price from a single Chainlink oracle
+// fetchPriceFromOracle fetches price from a single Chainlink oracle. Oracles
+// whose description differs from the configured one are rejected, as are
+// answers of incomplete rounds, non-positive or older than the max data age of
+// the oracle.
 func fetchPriceFromOracle(
 	ctx context.Context,
-	client *ethclient.Client,
+	caller bind.ContractCaller,
 	symbol types.Symbol,
 	config ChainlinkConfig,
-	logger zerolog.Logger,
-) (float64, error) {
+) (types.RawPrice, error) {
 	// Create oracle contract instance
-	oracle, err := chainlink.NewChainlinkAggregator(config.ContractAddress, client)
+	oracle, err := chainlink.NewChainlinkAggregatorCaller(config.ContractAddress, caller)
 	if err != nil {
-		return 0, fmt.Errorf("failed to create oracle contract: %w", err)
+		return types.RawPrice{}, fmt.Errorf("failed to create oracle contract: %w", err)
 	}
 
 	// Get oracle metadata
 	description, err := oracle.Description(&bind.CallOpts{Context: ctx})
 	if err != nil {
-		return 0, fmt.Errorf("failed to get description: %w", err)
+		return types.RawPrice{}, fmt.Errorf("failed to get description: %w", err)
 	}
 
 	decimals, err := oracle.Decimals(&bind.CallOpts{Context: ctx})
 	if err != nil {
-		return 0, fmt.Errorf("failed to get decimals: %w", err)
+		return types.RawPrice{}, fmt.Errorf("failed to get decimals: %w", err)
 	}
 
 	// Validate oracle
 	if config.Description != "" && description != config.Description {
-		logger.Warn().
-			Str("symbol", string(symbol)).
-			Str("expected", config.Description).
-			Str("actual", description).
-			Msg("oracle description mismatch")
+		return types.RawPrice{}, fmt.Errorf("oracle description mismatch for %s: expected %q, got %q",
+			symbol, config.Description, description)
 	}
 
 	// Get latest round data
 	roundData, err := oracle.LatestRoundData(&bind.CallOpts{Context: ctx})
 	if err != nil {
-		return 0, fmt.Errorf("failed to get latest round data: %w", err)
+		return types.RawPrice{}, fmt.Errorf("failed to get latest round data: %w", err)
 	}
 
-	// Check data freshness
-	now := time.Now()
-	updatedAt := time.Unix(int64(roundData.UpdatedAt.Uint64()), 0)
-	dataAge := now.Sub(updatedAt)
-
-	if config.MaxDataAge > 0 && dataAge > config.MaxDataAge {
-		logger.Warn().
-			Str("symbol", string(symbol)).
-			Dur("age", dataAge).
-			Dur("max_age", config.MaxDataAge).
-			Msg("oracle data is stale")
+	// Check round completeness
+	if roundData.UpdatedAt.Sign() == 0 {
+		return types.RawPrice{}, fmt.Errorf("round %s of %s is not complete", roundData.RoundId, symbol)
 	}
-
-	// Convert price
-	price, err := convertChainlinkPrice(roundData.Answer, decimals)
-	if err != nil {
-		return 0, fmt.Errorf("failed to convert price: %w", err)
+	if roundData.AnsweredInRound.Cmp(roundData.RoundId) < 0 {
+		return types.RawPrice{}, fmt.Errorf("stale answer for %s: answered in round %s, latest round is %s",
+			symbol, roundData.AnsweredInRound, roundData.RoundId)
 	}
-	return price, nil
-}
 
-// convertChainlinkPrice converts Chainlink's raw price answer to a float64
-func convertChainlinkPrice(answer *big.Int, decimals uint8) (float64, error) {
-	if answer == nil {
-		return 0, fmt.Errorf("answer is nil")
+	// Check answer
+	if roundData.Answer.Sign() <= 0 {
+		return types.RawPrice{}, fmt.Errorf("invalid answer for %s: %s", symbol, roundData.Answer)
 	}
 
-	divisor := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
-	answerFloat := new(big.Float).SetInt(answer)
-	result := new(big.Float).Quo(answerFloat, divisor)
+	// Check data freshness
+	updatedAt := time.Unix(roundData.UpdatedAt.Int64(), 0)
+	dataAge := time.Since(updatedAt)
+	if maxDataAge := time.Duration(config.MaxDataAge); maxDataAge > 0 && dataAge > maxDataAge {
+		return types.RawPrice{}, fmt.Errorf("stale answer for %s: updated %s ago, max data age is %s",
+			symbol, dataAge.Truncate(time.Second), maxDataAge)
+	}
 
-	price, _ := result.Float64()
-	return price, nil
+	// Convert price
+	price, err := convertFixedPointPrice(roundData.Answer, decimals)
+	if err != nil {
+		return types.RawPrice{}, fmt.Errorf("failed to convert price: %w", err)
+	}
+	return types.RawPrice{Price: price, UpdateTime: updatedAt}, nil
 }
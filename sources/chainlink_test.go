@@ -1,11 +1,16 @@
 package sources
 
 import (
-	"fmt"
+	"encoding/hex"
+	"encoding/json"
+	"io"
 	"math/big"
 	"testing"
+	"time"
 
 	"github.com/NibiruChain/nibiru/v2/x/common/set"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -20,42 +25,136 @@ func TestChainlinkPriceUpdate(t *testing.T) {
 	symbols.Add("uBTC/BTC")
 	symbols.Add("foo:bar")
 
-	prices, err := ChainlinkPriceUpdate(symbols, logger)
+	prices, err := ChainlinkPriceUpdate(nil)(symbols, logger)
 
 	require.NoError(t, err)
 	require.Len(t, prices, 1)
 
 	price := prices["uBTC/BTC"]
-	assert.Greater(t, price, 0.0)
+	assert.Greater(t, price.Price, 0.0)
 
 	_, unknownExists := prices["foo/bar"]
 	assert.False(t, unknownExists)
 }
 
-func TestConvertChainlinkPrice(t *testing.T) {
-	for idx, tc := range []struct {
-		answer   *big.Int
-		decimals uint8
-		want     float64
+func TestChainlinkPriceUpdateConfiguredFeeds(t *testing.T) {
+	var (
+		ethUSD     = gethcommon.HexToAddress("0x5f4eC3Df9cbd43714FE2740f5E3616155c5b8419")
+		stale      = gethcommon.HexToAddress("0x1111111111111111111111111111111111111111")
+		incomplete = gethcommon.HexToAddress("0x2222222222222222222222222222222222222222")
+		broken     = gethcommon.HexToAddress("0x3333333333333333333333333333333333333333")
+		negative   = gethcommon.HexToAddress("0x4444444444444444444444444444444444444444")
+		mismatch   = gethcommon.HexToAddress("0x5555555555555555555555555555555555555555")
+		updatedAt  = time.Now().Add(-10 * time.Minute).Truncate(time.Second)
+	)
+	selector := func(method string) string {
+		return hex.EncodeToString(evmCallSelector(method))
+	}
+	outputs := map[string][]byte{}
+	addFeed := func(feed gethcommon.Address, answer int64, updatedAt time.Time, roundID, answeredInRound int64) {
+		outputs[feed.Hex()+" "+selector("description()")] = mustPackABI(t, "string", "ETH / USD")
+		outputs[feed.Hex()+" "+selector("decimals()")] = mustPackABI(t, "uint8", uint8(8))
+		outputs[feed.Hex()+" "+selector("latestRoundData()")] = mustPackABI(t, "uint80,int256,uint256,uint256,uint80",
+			big.NewInt(roundID), big.NewInt(answer), big.NewInt(updatedAt.Unix()), big.NewInt(updatedAt.Unix()),
+			big.NewInt(answeredInRound))
+	}
+	addFeed(ethUSD, 345_678_000_000, updatedAt, 42, 42)
+	addFeed(stale, 345_678_000_000, updatedAt.Add(-2*time.Hour), 42, 42)
+	addFeed(incomplete, 345_678_000_000, updatedAt, 42, 41)
+	addFeed(negative, -1, updatedAt, 42, 42)
+	addFeed(mismatch, 345_678_000_000, updatedAt, 42, 42)
+
+	var networks []string
+	connect := func(network string, _ zerolog.Logger) (bind.ContractCaller, func(), error) {
+		networks = append(networks, network)
+		return testContractCaller{outputs: outputs}, func() {}, nil
+	}
+
+	config := json.RawMessage(`{"feeds": {
+		"ETH/USD": {"network": "ethereum", "address": "` + ethUSD.Hex() + `", "description": "ETH / USD", "max_data_age": "1h"},
+		"stale": {"network": "ethereum", "address": "` + stale.Hex() + `", "max_data_age": "1h"},
+		"incomplete": {"network": "ethereum", "address": "` + incomplete.Hex() + `"},
+		"broken": {"network": "ethereum", "address": "` + broken.Hex() + `"},
+		"negative": {"network": "ethereum", "address": "` + negative.Hex() + `"},
+		"mismatch": {"network": "ethereum", "address": "` + mismatch.Hex() + `", "description": "BTC / USD"}
+	}}`)
+
+	t.Run("success", func(t *testing.T) {
+		networks = nil
+		prices, err := chainlinkPriceUpdate(config, connect)(
+			set.New[types.Symbol]("ETH/USD", "stale", "incomplete", "broken", "negative", "mismatch"),
+			zerolog.New(io.Discard),
+		)
+		require.NoError(t, err)
+		require.Equal(t, map[types.Symbol]types.RawPrice{
+			"ETH/USD": {Price: 3456.78, UpdateTime: updatedAt},
+		}, prices)
+		require.Equal(t, []string{"ethereum"}, networks)
+	})
+
+	for symbol, wantErr := range map[types.Symbol]string{
+		"stale":      "stale answer for stale: updated 2h10m",
+		"incomplete": "stale answer for incomplete: answered in round 41, latest round is 42",
+		"broken":     "failed to get description",
+		"negative":   "invalid answer for negative: -1",
+		"mismatch":   `oracle description mismatch for mismatch: expected "BTC / USD", got "ETH / USD"`,
+	} {
+		t.Run(string(symbol), func(t *testing.T) {
+			_, err := chainlinkPriceUpdate(config, connect)(set.New(symbol), zerolog.New(io.Discard))
+			require.ErrorContains(t, err, wantErr)
+		})
+	}
+}
+
+func TestParseChainlinkConfig(t *testing.T) {
+	feeds, err := ParseChainlinkConfig(nil)
+	require.NoError(t, err)
+	require.Equal(t, defaultChainlinkFeeds, feeds)
+	for symbol, feed := range feeds {
+		require.Positive(t, feed.MaxDataAge, "default feed %s must enforce its heartbeat", symbol)
+	}
+
+	feeds, err = ParseChainlinkConfig(json.RawMessage(`{"max_price_age": "2m", "feeds": {
+		"ETH/USD": {"network": "ethereum", "address": "0x5f4eC3Df9cbd43714FE2740f5E3616155c5b8419", "max_data_age": "1h"}
+	}}`))
+	require.NoError(t, err)
+	require.Len(t, feeds, 2)
+	require.Equal(t, ChainlinkConfig{
+		Chain:           ChainEthereum,
+		ContractAddress: gethcommon.HexToAddress("0x5f4eC3Df9cbd43714FE2740f5E3616155c5b8419"),
+		MaxDataAge:      types.Duration(time.Hour),
+	}, feeds["ETH/USD"])
+	require.Equal(t, defaultChainlinkFeeds["uBTC/BTC"], feeds["uBTC/BTC"])
+
+	for name, tc := range map[string]struct {
+		config  string
+		wantErr string
 	}{
-		{
-			answer:   big.NewInt(5_000_000_000),
-			decimals: 8,
-			want:     50.0,
+		"unsupported network": {
+			config:  `{"feeds": {"ETH/USD": {"network": "solana", "address": "0x5f4eC3Df9cbd43714FE2740f5E3616155c5b8419"}}}`,
+			wantErr: "unsupported network: solana",
 		},
-		{
-			answer: new(big.Int).Mul(
-				big.NewInt(420_690),
-				new(big.Int).Exp(big.NewInt(10), big.NewInt(15), nil),
-			),
-			decimals: 18,
-			want:     420.69,
+		"missing address": {
+			config:  `{"feeds": {"ETH/USD": {"network": "ethereum"}}}`,
+			wantErr: "missing aggregator address",
+		},
+		"invalid address": {
+			config:  `{"feeds": {"ETH/USD": {"network": "ethereum", "address": "0x123"}}}`,
+			wantErr: "invalid chainlink config",
+		},
+		"negative max data age": {
+			config:  `{"feeds": {"ETH/USD": {"network": "ethereum", "address": "0x5f4eC3Df9cbd43714FE2740f5E3616155c5b8419", "max_data_age": "-1m"}}}`,
+			wantErr: "max data age must not be negative",
 		},
 	} {
-		t.Run(fmt.Sprintf("tc %d - %+v", idx, tc), func(t *testing.T) {})
-
-		gotPrice, err := convertChainlinkPrice(tc.answer, tc.decimals)
-		assert.NoError(t, err)
-		assert.Equal(t, tc.want, gotPrice)
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseChainlinkConfig(json.RawMessage(tc.config))
+			require.ErrorContains(t, err, tc.wantErr)
+		})
 	}
 }
+
+func TestChainlinkDefaultMaxPriceAge(t *testing.T) {
+	require.Equal(t, chainlinkMaxPriceAge, DefaultMaxPriceAge(SourceNameChainLink))
+	require.Equal(t, types.PriceTimeout, DefaultMaxPriceAge(SourceNameBinance))
+}
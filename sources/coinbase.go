@@ -0,0 +1,126 @@
+package sources
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/NibiruChain/nibiru/v2/x/common/set"
+	"github.com/rs/zerolog"
+
+	"github.com/NibiruChain/pricefeeder/metrics"
+	"github.com/NibiruChain/pricefeeder/types"
+)
+
+const (
+	SourceNameCoinbase = "coinbase"
+	CoinbaseProductURL = "https://api.exchange.coinbase.com/products/"
+	// coinbaseMaxPriceAge is the default max price age of the coinbase
+	// source, whose prices are as old as the last trade of their product,
+	// which can be minutes ago on thin books.
+	coinbaseMaxPriceAge = 5 * time.Minute
+)
+
+var _ types.FetchRawPricesFunc = CoinbasePriceUpdate
+
+// CoinbaseTicker is the ticker of a product returned by the Coinbase Exchange API.
+type CoinbaseTicker struct {
+	Price string `json:"price"`
+	// Volume is the 24 hours volume in the base asset.
+	Volume string `json:"volume"`
+	// Time is the time of the last trade, in RFC 3339 format.
+	Time string `json:"time"`
+}
+
+// CoinbasePriceUpdate returns the prices given the symbols or an error.
+// Coinbase has no batch ticker endpoint, so the ticker of each product is
+// fetched concurrently. Symbols are product ids, e.g. BTC-USD. The 24 hours
+// volume and the time of the last trade are reported with the price.
+// Uses the Coinbase Exchange API at https://docs.cdp.coinbase.com/exchange/reference/exchangerestapi_getproductticker.
+func CoinbasePriceUpdate(symbols set.Set[types.Symbol], logger zerolog.Logger) (rawPrices map[types.Symbol]types.RawPrice, err error) {
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		errs   []error
+		prices = make(map[types.Symbol]types.RawPrice)
+	)
+	for symbol := range symbols {
+		wg.Add(1)
+		go func(symbol types.Symbol) {
+			defer wg.Done()
+			price, err := fetchCoinbaseTicker(symbol, logger)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				logger.Err(err).Str("source", SourceNameCoinbase).Str("symbol", string(symbol)).Msg("failed to fetch price from Coinbase")
+				errs = append(errs, err)
+				return
+			}
+			prices[symbol] = price
+			logger.Debug().Msg(fmt.Sprintf("fetched price for %s on data source %s: %f", symbol, SourceNameCoinbase, price.Price))
+		}(symbol)
+	}
+	wg.Wait()
+
+	if len(prices) == 0 && len(errs) > 0 {
+		metrics.PriceSourceCounter.WithLabelValues(SourceNameCoinbase, "false").Inc()
+		return nil, errs[0]
+	}
+
+	metrics.PriceSourceCounter.WithLabelValues(SourceNameCoinbase, "true").Inc()
+	return prices, nil
+}
+
+// fetchCoinbaseTicker returns the last trade price of a single product, with
+// its volume and trade time when reported.
+func fetchCoinbaseTicker(symbol types.Symbol, logger zerolog.Logger) (types.RawPrice, error) {
+	resp, err := http.Get(CoinbaseProductURL + string(symbol) + "/ticker")
+	if err != nil {
+		return types.RawPrice{}, err
+	}
+	defer func() {
+		errClose := resp.Body.Close()
+		if errClose != nil {
+			errClose = fmt.Errorf("error closing response body: %w", errClose)
+			logger.Err(errClose).Str("source", SourceNameCoinbase).Msg(errClose.Error())
+		}
+	}()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return types.RawPrice{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return types.RawPrice{}, fmt.Errorf("unexpected status %d for %s: %s", resp.StatusCode, symbol, b)
+	}
+
+	var ticker CoinbaseTicker
+	if err := json.Unmarshal(b, &ticker); err != nil {
+		return types.RawPrice{}, err
+	}
+	price, err := strconv.ParseFloat(ticker.Price, 64)
+	if err != nil {
+		return types.RawPrice{}, fmt.Errorf("failed to parse price for %s: %w", symbol, err)
+	}
+
+	rawPrice := types.RawPrice{Price: price}
+	if ticker.Volume != "" {
+		rawPrice.Volume, err = strconv.ParseFloat(ticker.Volume, 64)
+		if err != nil {
+			logger.Err(err).Msg(fmt.Sprintf("failed to parse volume for %s on data source %s", symbol, SourceNameCoinbase))
+			rawPrice.Volume = 0
+		}
+	}
+	if ticker.Time != "" {
+		rawPrice.UpdateTime, err = time.Parse(time.RFC3339Nano, ticker.Time)
+		if err != nil {
+			logger.Err(err).Msg(fmt.Sprintf("failed to parse trade time for %s on data source %s", symbol, SourceNameCoinbase))
+			rawPrice.UpdateTime = time.Time{}
+		}
+	}
+	return rawPrice, nil
+}
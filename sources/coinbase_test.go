@@ -0,0 +1,68 @@
+package sources
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/NibiruChain/nibiru/v2/x/common/set"
+	"github.com/jarcoal/httpmock"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NibiruChain/pricefeeder/types"
+)
+
+func TestCoinbasePriceUpdate(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	t.Run("success", func(t *testing.T) {
+		httpmock.Reset()
+		httpmock.RegisterResponder(
+			"GET", CoinbaseProductURL+"BTC-USD/ticker",
+			httpmock.NewStringResponder(200, `{"ask":"60001.00","bid":"60000.00","volume":"12345.6","trade_id":1,"price":"60000.50","size":"0.01","time":"2024-01-01T00:00:00.000000Z"}`),
+		)
+		httpmock.RegisterResponder(
+			"GET", CoinbaseProductURL+"ETH-USD/ticker",
+			httpmock.NewStringResponder(200, `{"ask":"3001.00","bid":"3000.00","volume":"54321","trade_id":2,"price":"3000.25","size":"0.5","time":"2024-01-01T00:00:00.000000Z"}`),
+		)
+		prices, err := CoinbasePriceUpdate(set.New[types.Symbol]("BTC-USD", "ETH-USD"), zerolog.New(io.Discard))
+		require.NoError(t, err)
+
+		tradeTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		require.Equal(t, map[types.Symbol]types.RawPrice{
+			"BTC-USD": {Price: 60000.5, Volume: 12345.6, UpdateTime: tradeTime},
+			"ETH-USD": {Price: 3000.25, Volume: 54321, UpdateTime: tradeTime},
+		}, prices)
+	})
+
+	t.Run("partial failure", func(t *testing.T) {
+		httpmock.Reset()
+		httpmock.RegisterResponder(
+			"GET", CoinbaseProductURL+"BTC-USD/ticker",
+			httpmock.NewStringResponder(200, `{"price":"60000.50"}`),
+		)
+		httpmock.RegisterResponder(
+			"GET", CoinbaseProductURL+"FOO-USD/ticker",
+			httpmock.NewStringResponder(404, `{"message":"NotFound"}`),
+		)
+		prices, err := CoinbasePriceUpdate(set.New[types.Symbol]("BTC-USD", "FOO-USD"), zerolog.New(io.Discard))
+		require.NoError(t, err)
+		require.Equal(t, map[types.Symbol]types.RawPrice{"BTC-USD": {Price: 60000.5}}, prices)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		httpmock.Reset()
+		httpmock.RegisterResponder(
+			"GET", CoinbaseProductURL+"FOO-USD/ticker",
+			httpmock.NewStringResponder(404, `{"message":"NotFound"}`),
+		)
+		_, err := CoinbasePriceUpdate(set.New[types.Symbol]("FOO-USD"), zerolog.New(io.Discard))
+		require.ErrorContains(t, err, "unexpected status 404")
+	})
+}
+
+func TestCoinbaseDefaultMaxPriceAge(t *testing.T) {
+	require.Equal(t, coinbaseMaxPriceAge, DefaultMaxPriceAge(SourceNameCoinbase))
+}
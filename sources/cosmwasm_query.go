@@ -0,0 +1,185 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+	"github.com/NibiruChain/nibiru/v2/x/common/set"
+	"github.com/rs/zerolog"
+
+	"github.com/NibiruChain/pricefeeder/metrics"
+	"github.com/NibiruChain/pricefeeder/types"
+)
+
+const (
+	SourceNameCosmWasmQuery = "cosmwasm_query"
+)
+
+// CosmWasmQuery describes the smart query returning the price of a symbol.
+type CosmWasmQuery struct {
+	// Contract is the address of the contract to query.
+	Contract string `json:"contract"`
+	// Query is the JSON smart query message, e.g. {"state": {}}.
+	Query json.RawMessage `json:"query"`
+	// ResultPath is the JSONPath to the price in the query response, e.g.
+	// $.exchange_rate. The value may be a number or a string.
+	ResultPath string `json:"result_path"`
+	// Scale multiplies the price, e.g. to convert between decimals. Defaults
+	// to 1.
+	Scale float64 `json:"scale"`
+	// Invert reports the reciprocal of the scaled price.
+	Invert bool `json:"invert"`
+
+	resultPath jsonPath
+}
+
+// CosmWasmQueryConfig maps each symbol of the cosmwasm_query source to the
+// smart query returning its price.
+type CosmWasmQueryConfig struct {
+	Queries map[types.Symbol]*CosmWasmQuery `json:"queries"`
+}
+
+// ParseCosmWasmQueryConfig parses and validates the configuration of the
+// cosmwasm_query source.
+func ParseCosmWasmQueryConfig(jsonConfig json.RawMessage) (CosmWasmQueryConfig, error) {
+	var config CosmWasmQueryConfig
+	if len(jsonConfig) > 0 {
+		if err := json.Unmarshal(jsonConfig, &config); err != nil {
+			return config, fmt.Errorf("invalid cosmwasm_query config: %w", err)
+		}
+	}
+	for symbol, query := range config.Queries {
+		if query == nil {
+			return config, fmt.Errorf("invalid cosmwasm_query config for %s: no query", symbol)
+		}
+		if err := query.init(); err != nil {
+			return config, fmt.Errorf("invalid cosmwasm_query config for %s: %w", symbol, err)
+		}
+	}
+	return config, nil
+}
+
+// init validates the query and parses its result path.
+func (q *CosmWasmQuery) init() error {
+	if q.Contract == "" {
+		return fmt.Errorf("no contract")
+	}
+	if !json.Valid(q.Query) {
+		return fmt.Errorf("query is not valid JSON: %s", q.Query)
+	}
+	if q.Scale < 0 {
+		return fmt.Errorf("scale must not be negative: %f", q.Scale)
+	}
+	if q.Scale == 0 {
+		q.Scale = 1
+	}
+	path, err := parseJSONPath(q.ResultPath)
+	if err != nil {
+		return fmt.Errorf("result_path: %w", err)
+	}
+	q.resultPath = path
+	return nil
+}
+
+// price runs the smart query and returns the price extracted from its response.
+func (q *CosmWasmQuery) price(ctx context.Context, wasmClient wasmtypes.QueryClient) (float64, error) {
+	resp, err := wasmClient.SmartContractState(ctx, &wasmtypes.QuerySmartContractStateRequest{
+		Address:   q.Contract,
+		QueryData: wasmtypes.RawContractMessage(q.Query),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to query SmartContractState: %w", err)
+	}
+	if resp == nil || len(resp.Data) == 0 {
+		return 0, fmt.Errorf("nil response from SmartContractState")
+	}
+
+	var result any
+	if err := json.Unmarshal(resp.Data, &result); err != nil {
+		return 0, fmt.Errorf("failed to unmarshal SmartContractState response data: %w", err)
+	}
+	v, err := q.resultPath.lookup(result)
+	if err != nil {
+		return 0, fmt.Errorf("result_path: %w", err)
+	}
+	price, err := parseJSONNumber(v)
+	if err != nil {
+		return 0, fmt.Errorf("result_path: %w", err)
+	}
+
+	price *= q.Scale
+	if price <= 0 {
+		return 0, fmt.Errorf("received invalid price %f: value must be positive", price)
+	}
+	if q.Invert {
+		price = 1 / price
+	}
+	return price, nil
+}
+
+// CosmWasmQueryPriceUpdate returns the prices of the symbols given the smart
+// query configured for each of them, queried through GRPC_READ_ENDPOINT.
+func CosmWasmQueryPriceUpdate(cosmWasmQueryConfig json.RawMessage) types.FetchPricesFunc {
+	return cosmWasmQueryPriceUpdate(cosmWasmQueryConfig, func() (wasmtypes.QueryClient, func() error, error) {
+		conn, err := newGRPCConnection()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to connect to gRPC endpoint %s: %w", grpcReadEndpoint, err)
+		}
+		return wasmtypes.NewQueryClient(conn), conn.Close, nil
+	})
+}
+
+func cosmWasmQueryPriceUpdate(
+	cosmWasmQueryConfig json.RawMessage,
+	newWasmClient func() (wasmtypes.QueryClient, func() error, error),
+) types.FetchPricesFunc {
+	return func(symbols set.Set[types.Symbol], logger zerolog.Logger) (map[types.Symbol]float64, error) {
+		config, err := ParseCosmWasmQueryConfig(cosmWasmQueryConfig)
+		if err != nil {
+			logger.Err(err).Msg("failed to extract cosmwasm_query config")
+			metrics.PriceSourceCounter.WithLabelValues(SourceNameCosmWasmQuery, "false").Inc()
+			return nil, err
+		}
+
+		wasmClient, closeClient, err := newWasmClient()
+		if err != nil {
+			logger.Err(err).Msg("failed to create wasm query client")
+			metrics.PriceSourceCounter.WithLabelValues(SourceNameCosmWasmQuery, "false").Inc()
+			return nil, err
+		}
+		defer func() {
+			if closeErr := closeClient(); closeErr != nil {
+				logger.Err(closeErr).Str("source", SourceNameCosmWasmQuery).Msg("failed to close gRPC connection")
+			}
+		}()
+
+		var lastErr error
+		rawPrices := make(map[types.Symbol]float64)
+		for symbol := range symbols {
+			query, ok := config.Queries[symbol]
+			if !ok {
+				lastErr = fmt.Errorf("no query configured for %s", symbol)
+				logger.Error().Str("source", SourceNameCosmWasmQuery).Str("symbol", string(symbol)).Msg("no query configured")
+				continue
+			}
+			price, err := query.price(context.Background(), wasmClient)
+			if err != nil {
+				lastErr = err
+				logger.Err(err).Str("source", SourceNameCosmWasmQuery).Str("symbol", string(symbol)).Str("contract", query.Contract).Msg("failed to query price")
+				continue
+			}
+
+			rawPrices[symbol] = price
+			logger.Debug().Msg(fmt.Sprintf("fetched price for %s on data source %s: %f", symbol, SourceNameCosmWasmQuery, price))
+		}
+
+		if len(rawPrices) == 0 && lastErr != nil {
+			metrics.PriceSourceCounter.WithLabelValues(SourceNameCosmWasmQuery, "false").Inc()
+			return nil, lastErr
+		}
+		metrics.PriceSourceCounter.WithLabelValues(SourceNameCosmWasmQuery, "true").Inc()
+		return rawPrices, nil
+	}
+}
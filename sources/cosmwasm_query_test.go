@@ -0,0 +1,92 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+	"github.com/NibiruChain/nibiru/v2/x/common/set"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/NibiruChain/pricefeeder/types"
+)
+
+// testWasmQueryClient answers smart queries from a map keyed by contract
+// address and query data.
+type testWasmQueryClient struct {
+	wasmtypes.QueryClient
+	responses map[string]string
+}
+
+func (c testWasmQueryClient) SmartContractState(
+	_ context.Context, req *wasmtypes.QuerySmartContractStateRequest, _ ...grpc.CallOption,
+) (*wasmtypes.QuerySmartContractStateResponse, error) {
+	resp, ok := c.responses[req.Address+" "+string(req.QueryData)]
+	if !ok {
+		return nil, fmt.Errorf("contract %s: unknown query %s", req.Address, req.QueryData)
+	}
+	return &wasmtypes.QuerySmartContractStateResponse{Data: []byte(resp)}, nil
+}
+
+func TestCosmWasmQueryPriceUpdate(t *testing.T) {
+	client := testWasmQueryClient{responses: map[string]string{
+		`nibi1eris {"state":{}}`:              `{"total_ustake": "1000", "exchange_rate": "1.0523"}`,
+		`nibi1vault {"share_price":{"id":1}}`: `{"price": {"amount": 2500000}}`,
+	}}
+	newClient := func() (wasmtypes.QueryClient, func() error, error) {
+		return client, func() error { return nil }, nil
+	}
+
+	config := json.RawMessage(`{"queries": {
+		"stnibi": {"contract": "nibi1eris", "query": {"state":{}}, "result_path": "$.exchange_rate"},
+		"nibistnibi": {"contract": "nibi1eris", "query": {"state":{}}, "result_path": "exchange_rate", "invert": true},
+		"vault": {"contract": "nibi1vault", "query": {"share_price":{"id":1}}, "result_path": "$.price.amount", "scale": 0.000001},
+		"broken": {"contract": "nibi1broken", "query": {"state":{}}, "result_path": "$.exchange_rate"}
+	}}`)
+
+	t.Run("success", func(t *testing.T) {
+		rawPrices, err := cosmWasmQueryPriceUpdate(config, newClient)(
+			set.New[types.Symbol]("stnibi", "nibistnibi", "vault", "broken", "unconfigured"),
+			zerolog.New(io.Discard),
+		)
+		require.NoError(t, err)
+		require.Len(t, rawPrices, 3)
+		require.Equal(t, 1.0523, rawPrices["stnibi"])
+		require.InDelta(t, 1/1.0523, rawPrices["nibistnibi"], 1e-12)
+		require.InDelta(t, 2.5, rawPrices["vault"], 1e-12)
+	})
+
+	t.Run("every query fails", func(t *testing.T) {
+		_, err := cosmWasmQueryPriceUpdate(config, newClient)(set.New[types.Symbol]("broken"), zerolog.New(io.Discard))
+		require.ErrorContains(t, err, "unknown query")
+	})
+}
+
+func TestParseCosmWasmQueryConfig(t *testing.T) {
+	testCases := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{"valid", `{"queries": {"stnibi": {"contract": "nibi1eris", "query": {"state": {}}, "result_path": "$.exchange_rate"}}}`, ""},
+		{"no contract", `{"queries": {"stnibi": {"query": {"state": {}}}}}`, "no contract"},
+		{"no query", `{"queries": {"stnibi": {"contract": "nibi1eris"}}}`, "query is not valid JSON"},
+		{"invalid result path", `{"queries": {"stnibi": {"contract": "nibi1eris", "query": {}, "result_path": "$.rates[*]"}}}`, "result_path"},
+		{"negative scale", `{"queries": {"stnibi": {"contract": "nibi1eris", "query": {}, "scale": -1}}}`, "scale must not be negative"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseCosmWasmQueryConfig(json.RawMessage(tc.config))
+			if tc.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/NibiruChain/nibiru/v2/x/common/set"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog"
+
+	"github.com/NibiruChain/pricefeeder/metrics"
+	"github.com/NibiruChain/pricefeeder/types"
+)
+
+const (
+	SourceNameERC4626 = "erc4626"
+)
+
+// ERC4626Vault describes an ERC-4626 vault whose share price, in units of
+// its underlying asset, is the price of a symbol.
+type ERC4626Vault struct {
+	// Network is the name of the EVM network, e.g. ethereum.
+	Network string `json:"network"`
+	// Vault is the address of the vault contract.
+	Vault string `json:"vault"`
+}
+
+// ERC4626Config maps each symbol of the erc4626 source to its vault.
+type ERC4626Config struct {
+	Vaults map[types.Symbol]ERC4626Vault `json:"vaults"`
+}
+
+// ParseERC4626Config parses and validates the configuration of the erc4626
+// source.
+func ParseERC4626Config(jsonConfig json.RawMessage) (ERC4626Config, error) {
+	var config ERC4626Config
+	if len(jsonConfig) > 0 {
+		if err := json.Unmarshal(jsonConfig, &config); err != nil {
+			return config, fmt.Errorf("invalid erc4626 config: %w", err)
+		}
+	}
+	for symbol, vault := range config.Vaults {
+		if _, err := types.GetRPCEndpoints(vault.Network); err != nil {
+			return config, fmt.Errorf("invalid erc4626 config for %s: %w", symbol, err)
+		}
+		if !gethcommon.IsHexAddress(vault.Vault) {
+			return config, fmt.Errorf("invalid erc4626 config for %s: invalid vault address: %q", symbol, vault.Vault)
+		}
+	}
+	return config, nil
+}
+
+// sharePrice returns the amount of underlying assets one share of the vault
+// converts to, calling convertToAssets(10**decimals) on the vault.
+func (v ERC4626Vault) sharePrice(ctx context.Context, caller bind.ContractCaller) (float64, error) {
+	shareDecimals, err := evmDecimals(ctx, caller, v.Network, v.Vault)
+	if err != nil {
+		return 0, err
+	}
+	assetCall, err := newEVMCall(v.Network, v.Vault, "asset()(address)")
+	if err != nil {
+		return 0, err
+	}
+	asset, err := assetCall.result(ctx, caller)
+	if err != nil {
+		return 0, err
+	}
+	assetAddress, ok := asset.(gethcommon.Address)
+	if !ok {
+		return 0, fmt.Errorf("unexpected asset type %T", asset)
+	}
+	assetDecimals, err := evmDecimals(ctx, caller, v.Network, assetAddress.Hex())
+	if err != nil {
+		return 0, err
+	}
+
+	oneShare := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(shareDecimals)), nil)
+	convertCall, err := newEVMCall(v.Network, v.Vault, "convertToAssets(uint256)(uint256)", oneShare.String())
+	if err != nil {
+		return 0, err
+	}
+	convertCall.Decimals = assetDecimals
+	return convertCall.price(ctx, caller)
+}
+
+// evmDecimals returns the decimals of an ERC-20 token, such as a vault share.
+func evmDecimals(ctx context.Context, caller bind.ContractCaller, network, contract string) (uint8, error) {
+	call, err := newEVMCall(network, contract, "decimals()(uint8)")
+	if err != nil {
+		return 0, err
+	}
+	decimals, err := call.result(ctx, caller)
+	if err != nil {
+		return 0, err
+	}
+	d, ok := decimals.(uint8)
+	if !ok {
+		return 0, fmt.Errorf("unexpected decimals type %T", decimals)
+	}
+	return d, nil
+}
+
+// ERC4626PriceUpdate returns the share prices of the vaults configured for the
+// symbols, connecting to each network with RPC failover.
+func ERC4626PriceUpdate(erc4626Config json.RawMessage) types.FetchPricesFunc {
+	return erc4626PriceUpdate(erc4626Config, connectToEVMNetwork)
+}
+
+func erc4626PriceUpdate(erc4626Config json.RawMessage, connect evmConnectFunc) types.FetchPricesFunc {
+	return func(symbols set.Set[types.Symbol], logger zerolog.Logger) (map[types.Symbol]float64, error) {
+		config, err := ParseERC4626Config(erc4626Config)
+		if err != nil {
+			logger.Err(err).Msg("failed to extract erc4626 config")
+			metrics.PriceSourceCounter.WithLabelValues(SourceNameERC4626, "false").Inc()
+			return nil, err
+		}
+
+		networks := make(map[types.Symbol]string, len(config.Vaults))
+		for symbol, vault := range config.Vaults {
+			networks[symbol] = vault.Network
+		}
+		return fetchEVMPrices(SourceNameERC4626, symbols, networks, connect,
+			func(ctx context.Context, caller bind.ContractCaller, symbol types.Symbol) (float64, error) {
+				return config.Vaults[symbol].sharePrice(ctx, caller)
+			}, logger)
+	}
+}
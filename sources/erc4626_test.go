@@ -0,0 +1,77 @@
+package sources
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"math/big"
+	"testing"
+
+	"github.com/NibiruChain/nibiru/v2/x/common/set"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NibiruChain/pricefeeder/types"
+)
+
+func TestERC4626PriceUpdate(t *testing.T) {
+	var (
+		susda = gethcommon.HexToAddress("0x1111111111111111111111111111111111111111")
+		usda  = gethcommon.HexToAddress("0x2222222222222222222222222222222222222222")
+		susdc = gethcommon.HexToAddress("0x3333333333333333333333333333333333333333")
+		usdc  = gethcommon.HexToAddress("0x4444444444444444444444444444444444444444")
+	)
+	e18 := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+	convertToAssets := func(shares *big.Int) string {
+		return hex.EncodeToString(append(evmCallSelector("convertToAssets(uint256)"), mustPackABI(t, "uint256", shares)...))
+	}
+
+	outputs := map[string][]byte{
+		// 18 decimals vault of an 18 decimals asset
+		susda.Hex() + " 313ce567": mustPackABI(t, "uint8", uint8(18)),
+		susda.Hex() + " 38d52e0f": mustPackABI(t, "address", usda),
+		usda.Hex() + " 313ce567":  mustPackABI(t, "uint8", uint8(18)),
+		susda.Hex() + " " + convertToAssets(e18): mustPackABI(t, "uint256",
+			new(big.Int).Mul(big.NewInt(108084207), big.NewInt(1e10))),
+		// 18 decimals vault of a 6 decimals asset
+		susdc.Hex() + " 313ce567":                mustPackABI(t, "uint8", uint8(18)),
+		susdc.Hex() + " 38d52e0f":                mustPackABI(t, "address", usdc),
+		usdc.Hex() + " 313ce567":                 mustPackABI(t, "uint8", uint8(6)),
+		susdc.Hex() + " " + convertToAssets(e18): mustPackABI(t, "uint256", big.NewInt(1_050_000)),
+	}
+	connect := func(string, zerolog.Logger) (bind.ContractCaller, func(), error) {
+		return testContractCaller{outputs: outputs}, func() {}, nil
+	}
+
+	config := json.RawMessage(`{"vaults": {
+		"susda": {"network": "ethereum", "vault": "` + susda.Hex() + `"},
+		"susdc": {"network": "ethereum", "vault": "` + susdc.Hex() + `"},
+		"broken": {"network": "ethereum", "vault": "0x5555555555555555555555555555555555555555"}
+	}}`)
+
+	t.Run("success", func(t *testing.T) {
+		prices, err := erc4626PriceUpdate(config, connect)(
+			set.New[types.Symbol]("susda", "susdc", "broken"),
+			zerolog.New(io.Discard),
+		)
+		require.NoError(t, err)
+		require.Len(t, prices, 2)
+		require.InDelta(t, 1.08084207, prices["susda"], 1e-12)
+		require.InDelta(t, 1.05, prices["susdc"], 1e-12)
+	})
+
+	t.Run("every vault fails", func(t *testing.T) {
+		_, err := erc4626PriceUpdate(config, connect)(set.New[types.Symbol]("broken"), zerolog.New(io.Discard))
+		require.ErrorContains(t, err, "failed to call decimals()(uint8)")
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := ParseERC4626Config(json.RawMessage(`{"vaults": {"susda": {"network": "ethereum", "vault": "0x123"}}}`))
+		require.ErrorContains(t, err, "invalid vault address")
+
+		_, err = ParseERC4626Config(json.RawMessage(`{"vaults": {"susda": {"network": "solana", "vault": "` + susda.Hex() + `"}}}`))
+		require.ErrorContains(t, err, "unsupported network: solana")
+	})
+}
@@ -4,10 +4,8 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"os"
-	"strconv"
 	"strings"
 
 	"google.golang.org/grpc/credentials/insecure"
@@ -74,47 +72,21 @@ func ErisProtocolPriceUpdate(symbols set.Set[types.Symbol], logger zerolog.Logge
 		contractAddr = "nibi1udqqx30cw8nwjxtl4l28ym9hhrp933zlq8dqxfjzcdhvl8y24zcqpzmh8m" // mainnet
 	}
 
-	wasmClient := wasmtypes.NewQueryClient(conn)
-	query := wasmtypes.QuerySmartContractStateRequest{
-		Address:   contractAddr,
-		QueryData: []byte(stateQuery),
+	query := CosmWasmQuery{
+		Contract:   contractAddr,
+		Query:      json.RawMessage(stateQuery),
+		ResultPath: "exchange_rate",
 	}
-
-	resp, err := wasmClient.SmartContractState(context.Background(), &query)
-	if err != nil {
-		logger.Err(err).Msg("failed to query SmartContractState")
+	if err := query.init(); err != nil {
+		logger.Err(err).Msg("failed to build exchange rate query")
 		metrics.PriceSourceCounter.WithLabelValues(SourceNameErisProtocol, "false").Inc()
-		return nil, fmt.Errorf("failed to query SmartContractState: %w", err)
-	}
-
-	if resp == nil || len(resp.Data) == 0 {
-		logger.Error().Msg("received nil or empty response from SmartContractState")
-		metrics.PriceSourceCounter.WithLabelValues(SourceNameErisProtocol, "false").Inc()
-		return nil, fmt.Errorf("nil response from SmartContractState")
-	}
-
-	var responseObj struct {
-		ExchangeRate string `json:"exchange_rate"`
+		return nil, err
 	}
-	if err := json.Unmarshal(resp.Data, &responseObj); err != nil {
-		logger.Err(err).Msg("failed to unmarshal SmartContractState response data")
-		metrics.PriceSourceCounter.WithLabelValues(SourceNameErisProtocol, "false").Inc()
-		return nil, fmt.Errorf("failed to unmarshal SmartContractState response data: %w", err)
-	}
-
-	exchangeRate, err := strconv.ParseFloat(responseObj.ExchangeRate, 64)
+	exchangeRate, err := query.price(context.Background(), wasmtypes.NewQueryClient(conn))
 	if err != nil {
-		logger.Err(err).Msg("failed to convert exchange_rate to float")
-		metrics.PriceSourceCounter.WithLabelValues(SourceNameErisProtocol, "false").Inc()
-		return nil, fmt.Errorf("failed to convert exchange_rate to float: %w", err)
-	}
-
-	// Validate the exchange rate
-	if exchangeRate <= 0 {
-		errMsg := "received invalid exchange rate: value must be positive"
-		logger.Error().Float64("exchange_rate", exchangeRate).Msg(errMsg)
+		logger.Err(err).Msg("failed to query exchange rate")
 		metrics.PriceSourceCounter.WithLabelValues(SourceNameErisProtocol, "false").Inc()
-		return nil, errors.New(errMsg)
+		return nil, err
 	}
 
 	rawPrices = make(map[types.Symbol]float64)
@@ -0,0 +1,385 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NibiruChain/nibiru/v2/x/common/set"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rs/zerolog"
+
+	"github.com/NibiruChain/pricefeeder/metrics"
+	"github.com/NibiruChain/pricefeeder/types"
+)
+
+const (
+	SourceNameEVMCall = "evm_call"
+
+	evmCallTimeout = 10 * time.Second
+)
+
+// EVMCall describes the contract call returning the price of a symbol.
+type EVMCall struct {
+	// Network is the name of the EVM network, e.g. ethereum.
+	Network string `json:"network"`
+	// Contract is the address of the contract to call.
+	Contract string `json:"contract"`
+	// Method is the signature of the method with its return types, e.g.
+	// stEthPerToken()(uint256) or getRate(address)(uint256,uint256). The
+	// return types default to (uint256).
+	Method string `json:"method"`
+	// Args are the arguments of the method, as strings, e.g. "1000000".
+	Args []string `json:"args"`
+	// ReturnIndex is the index of the returned value holding the price.
+	ReturnIndex int `json:"return_index"`
+	// Decimals divides the returned integer by 10^decimals.
+	Decimals uint8 `json:"decimals"`
+	// Invert reports the reciprocal of the price.
+	Invert bool `json:"invert"`
+
+	data    []byte
+	outputs abi.Arguments
+}
+
+// EVMCallConfig maps each symbol of the evm_call source to the contract call
+// returning its price.
+type EVMCallConfig struct {
+	Calls map[types.Symbol]*EVMCall `json:"calls"`
+}
+
+// ParseEVMCallConfig parses and validates the configuration of the evm_call
+// source.
+func ParseEVMCallConfig(jsonConfig json.RawMessage) (EVMCallConfig, error) {
+	var config EVMCallConfig
+	if len(jsonConfig) > 0 {
+		if err := json.Unmarshal(jsonConfig, &config); err != nil {
+			return config, fmt.Errorf("invalid evm_call config: %w", err)
+		}
+	}
+	for symbol, call := range config.Calls {
+		if call == nil {
+			return config, fmt.Errorf("invalid evm_call config for %s: no call", symbol)
+		}
+		if err := call.init(); err != nil {
+			return config, fmt.Errorf("invalid evm_call config for %s: %w", symbol, err)
+		}
+		if returnType := call.outputs[call.ReturnIndex].Type; returnType.T != abi.UintTy && returnType.T != abi.IntTy {
+			return config, fmt.Errorf("invalid evm_call config for %s: return value %d is not an integer: %s", symbol, call.ReturnIndex, returnType)
+		}
+	}
+	return config, nil
+}
+
+// newEVMCall returns an initialized call of the method on the contract.
+func newEVMCall(network, contract, method string, args ...string) (*EVMCall, error) {
+	call := &EVMCall{Network: network, Contract: contract, Method: method, Args: args}
+	if err := call.init(); err != nil {
+		return nil, err
+	}
+	return call, nil
+}
+
+// evmMethodRegex matches method signatures such as name(address,uint256)(uint256).
+var evmMethodRegex = regexp.MustCompile(`^([A-Za-z_$][A-Za-z0-9_$]*)\(([^()]*)\)(?:\(([^()]*)\))?$`)
+
+// init validates the call, and encodes its call data.
+func (c *EVMCall) init() error {
+	if _, err := types.GetRPCEndpoints(c.Network); err != nil {
+		return err
+	}
+	if !gethcommon.IsHexAddress(c.Contract) {
+		return fmt.Errorf("invalid contract address: %q", c.Contract)
+	}
+
+	match := evmMethodRegex.FindStringSubmatch(strings.ReplaceAll(c.Method, " ", ""))
+	if match == nil {
+		return fmt.Errorf("invalid method signature: %q", c.Method)
+	}
+	name, inputTypes, outputTypes := match[1], match[2], match[3]
+	if outputTypes == "" {
+		outputTypes = "uint256"
+	}
+	inputs, err := parseABIArguments(inputTypes)
+	if err != nil {
+		return fmt.Errorf("invalid method inputs: %w", err)
+	}
+	c.outputs, err = parseABIArguments(outputTypes)
+	if err != nil {
+		return fmt.Errorf("invalid method outputs: %w", err)
+	}
+	if c.ReturnIndex < 0 || c.ReturnIndex >= len(c.outputs) {
+		return fmt.Errorf("return index %d out of range of %d return values", c.ReturnIndex, len(c.outputs))
+	}
+	if len(c.Args) != len(inputs) {
+		return fmt.Errorf("method takes %d arguments, got %d", len(inputs), len(c.Args))
+	}
+	args := make([]any, len(inputs))
+	for i, input := range inputs {
+		if args[i], err = parseABIValue(input.Type, c.Args[i]); err != nil {
+			return fmt.Errorf("invalid argument %d: %w", i, err)
+		}
+	}
+	packed, err := inputs.Pack(args...)
+	if err != nil {
+		return fmt.Errorf("failed to encode arguments: %w", err)
+	}
+	selector := crypto.Keccak256([]byte(name + "(" + inputTypes + ")"))[:4]
+	c.data = append(selector, packed...)
+	return nil
+}
+
+// parseABIArguments parses a comma-separated list of ABI types.
+func parseABIArguments(typeList string) (abi.Arguments, error) {
+	var arguments abi.Arguments
+	if typeList == "" {
+		return arguments, nil
+	}
+	for _, typeName := range strings.Split(typeList, ",") {
+		t, err := abi.NewType(typeName, "", nil)
+		if err != nil {
+			return nil, err
+		}
+		arguments = append(arguments, abi.Argument{Type: t})
+	}
+	return arguments, nil
+}
+
+// parseABIValue parses an argument of an integer, address or bool type.
+func parseABIValue(t abi.Type, s string) (any, error) {
+	switch t.T {
+	case abi.UintTy, abi.IntTy:
+		v, ok := new(big.Int).SetString(s, 0)
+		if !ok {
+			return nil, fmt.Errorf("invalid integer: %q", s)
+		}
+		return abiInteger(t, v)
+	case abi.AddressTy:
+		if !gethcommon.IsHexAddress(s) {
+			return nil, fmt.Errorf("invalid address: %q", s)
+		}
+		return gethcommon.HexToAddress(s), nil
+	case abi.BoolTy:
+		return strconv.ParseBool(s)
+	default:
+		return nil, fmt.Errorf("unsupported argument type %s", t)
+	}
+}
+
+// abiInteger converts the integer to the Go type abi.Pack expects for the
+// ABI type: a sized integer type for 8, 16, 32 and 64 bits, *big.Int otherwise.
+func abiInteger(t abi.Type, v *big.Int) (any, error) {
+	if t.T == abi.UintTy && (v.Sign() < 0 || v.BitLen() > t.Size) ||
+		t.T == abi.IntTy && v.BitLen() >= t.Size {
+		return nil, fmt.Errorf("integer %s overflows %s", v, t)
+	}
+	goType := t.GetType()
+	if goType == reflect.TypeOf(v) {
+		return v, nil
+	}
+	rv := reflect.New(goType).Elem()
+	if t.T == abi.UintTy {
+		rv.SetUint(v.Uint64())
+	} else {
+		rv.SetInt(v.Int64())
+	}
+	return rv.Interface(), nil
+}
+
+// result calls the contract and returns the value at the return index.
+func (c *EVMCall) result(ctx context.Context, caller bind.ContractCaller) (any, error) {
+	contract := gethcommon.HexToAddress(c.Contract)
+	output, err := caller.CallContract(ctx, ethereum.CallMsg{To: &contract, Data: c.data}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call %s: %w", c.Method, err)
+	}
+	values, err := c.outputs.Unpack(output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the result of %s: %w", c.Method, err)
+	}
+	return values[c.ReturnIndex], nil
+}
+
+// price calls the contract and returns the price from the returned values.
+func (c *EVMCall) price(ctx context.Context, caller bind.ContractCaller) (float64, error) {
+	result, err := c.result(ctx, caller)
+	if err != nil {
+		return 0, err
+	}
+	value, err := bigIntValue(result)
+	if err != nil {
+		return 0, err
+	}
+	price, err := convertFixedPointPrice(value, c.Decimals)
+	if err != nil {
+		return 0, err
+	}
+	if price <= 0 {
+		return 0, fmt.Errorf("received invalid price %f: value must be positive", price)
+	}
+	if c.Invert {
+		price = 1 / price
+	}
+	return price, nil
+}
+
+// bigIntValue converts an unpacked integer of any size to a big.Int.
+func bigIntValue(v any) (*big.Int, error) {
+	if v, ok := v.(*big.Int); ok {
+		return v, nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Int).SetUint64(rv.Uint()), nil
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(rv.Int()), nil
+	default:
+		return nil, fmt.Errorf("unexpected integer type %T", v)
+	}
+}
+
+// convertFixedPointPrice converts a fixed-point integer with the given number
+// of decimals, as returned by contracts, to a float64.
+func convertFixedPointPrice(value *big.Int, decimals uint8) (float64, error) {
+	if value == nil {
+		return 0, fmt.Errorf("value is nil")
+	}
+
+	divisor := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+	result := new(big.Float).Quo(new(big.Float).SetInt(value), divisor)
+
+	price, _ := result.Float64()
+	return price, nil
+}
+
+// evmConnectFunc connects to an EVM network, returning the contract caller
+// and a function to close the connection.
+type evmConnectFunc func(network string, logger zerolog.Logger) (bind.ContractCaller, func(), error)
+
+// connectToEVMNetwork connects to the network with RPC failover.
+func connectToEVMNetwork(network string, logger zerolog.Logger) (bind.ContractCaller, func(), error) {
+	client, err := types.ConnectToNetwork(network, evmCallTimeout, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, client.Close, nil
+}
+
+// EVMCallPriceUpdate returns the prices of the symbols given the contract call
+// configured for each of them, connecting to each network with RPC failover.
+func EVMCallPriceUpdate(evmCallConfig json.RawMessage) types.FetchPricesFunc {
+	return evmCallPriceUpdate(evmCallConfig, connectToEVMNetwork)
+}
+
+func evmCallPriceUpdate(evmCallConfig json.RawMessage, connect evmConnectFunc) types.FetchPricesFunc {
+	return func(symbols set.Set[types.Symbol], logger zerolog.Logger) (map[types.Symbol]float64, error) {
+		config, err := ParseEVMCallConfig(evmCallConfig)
+		if err != nil {
+			logger.Err(err).Msg("failed to extract evm_call config")
+			metrics.PriceSourceCounter.WithLabelValues(SourceNameEVMCall, "false").Inc()
+			return nil, err
+		}
+
+		networks := make(map[types.Symbol]string, len(config.Calls))
+		for symbol, call := range config.Calls {
+			networks[symbol] = call.Network
+		}
+		return fetchEVMPrices(SourceNameEVMCall, symbols, networks, connect,
+			func(ctx context.Context, caller bind.ContractCaller, symbol types.Symbol) (float64, error) {
+				return config.Calls[symbol].price(ctx, caller)
+			}, logger)
+	}
+}
+
+// fetchEVMPrices prices the symbols of an EVM source, connecting once to each
+// of their networks. Symbols without a network are skipped. An error is
+// returned only if no price could be fetched.
+func fetchEVMPrices(
+	sourceName string,
+	symbols set.Set[types.Symbol],
+	networks map[types.Symbol]string,
+	connect evmConnectFunc,
+	price func(ctx context.Context, caller bind.ContractCaller, symbol types.Symbol) (float64, error),
+	logger zerolog.Logger,
+) (map[types.Symbol]float64, error) {
+	rawPrices, err := fetchEVMRawPrices(sourceName, symbols, networks, connect,
+		func(ctx context.Context, caller bind.ContractCaller, symbol types.Symbol) (types.RawPrice, error) {
+			p, err := price(ctx, caller, symbol)
+			return types.RawPrice{Price: p}, err
+		}, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	prices := make(map[types.Symbol]float64, len(rawPrices))
+	for symbol, rawPrice := range rawPrices {
+		prices[symbol] = rawPrice.Price
+	}
+	return prices, nil
+}
+
+// fetchEVMRawPrices is like fetchEVMPrices for sources reporting the update
+// time of their prices.
+func fetchEVMRawPrices(
+	sourceName string,
+	symbols set.Set[types.Symbol],
+	networks map[types.Symbol]string,
+	connect evmConnectFunc,
+	price func(ctx context.Context, caller bind.ContractCaller, symbol types.Symbol) (types.RawPrice, error),
+	logger zerolog.Logger,
+) (map[types.Symbol]types.RawPrice, error) {
+	var lastErr error
+	symbolsByNetwork := make(map[string][]types.Symbol)
+	for symbol := range symbols {
+		network, ok := networks[symbol]
+		if !ok {
+			lastErr = fmt.Errorf("%s is not configured", symbol)
+			logger.Error().Str("source", sourceName).Str("symbol", string(symbol)).Msg("symbol not configured")
+			continue
+		}
+		symbolsByNetwork[network] = append(symbolsByNetwork[network], symbol)
+	}
+
+	rawPrices := make(map[types.Symbol]types.RawPrice)
+	for network, networkSymbols := range symbolsByNetwork {
+		caller, closeCaller, err := connect(network, logger)
+		if err != nil {
+			lastErr = err
+			logger.Err(err).Str("source", sourceName).Str("network", network).Msg("failed to connect to network")
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), evmCallTimeout)
+		for _, symbol := range networkSymbols {
+			p, err := price(ctx, caller, symbol)
+			if err != nil {
+				lastErr = err
+				logger.Err(err).Str("source", sourceName).Str("symbol", string(symbol)).Msg("failed to fetch price")
+				continue
+			}
+
+			rawPrices[symbol] = p
+			logger.Debug().Msg(fmt.Sprintf("fetched price for %s on data source %s: %f", symbol, sourceName, p.Price))
+		}
+		cancel()
+		closeCaller()
+	}
+
+	if len(rawPrices) == 0 && lastErr != nil {
+		metrics.PriceSourceCounter.WithLabelValues(sourceName, "false").Inc()
+		return nil, lastErr
+	}
+	metrics.PriceSourceCounter.WithLabelValues(sourceName, "true").Inc()
+	return rawPrices, nil
+}
@@ -0,0 +1,156 @@
+package sources
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"testing"
+
+	"github.com/NibiruChain/nibiru/v2/x/common/set"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NibiruChain/pricefeeder/types"
+)
+
+// testContractCaller answers contract calls from a map keyed by contract
+// address and hex call data.
+type testContractCaller struct {
+	bind.ContractCaller
+	outputs map[string][]byte
+}
+
+func (c testContractCaller) CallContract(_ context.Context, call ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	output, ok := c.outputs[call.To.Hex()+" "+hex.EncodeToString(call.Data)]
+	if !ok {
+		return nil, fmt.Errorf("execution reverted")
+	}
+	return output, nil
+}
+
+func mustPackABI(t *testing.T, typeList string, values ...any) []byte {
+	arguments, err := parseABIArguments(typeList)
+	require.NoError(t, err)
+	packed, err := arguments.Pack(values...)
+	require.NoError(t, err)
+	return packed
+}
+
+func TestEVMCallPriceUpdate(t *testing.T) {
+	wstETH := gethcommon.HexToAddress("0x7f39C581F595B53c5cb19bD0b3f8dA6c935E2Ca0")
+	rateProvider := gethcommon.HexToAddress("0x1111111111111111111111111111111111111111")
+	token := gethcommon.HexToAddress("0x2222222222222222222222222222222222222222")
+
+	caller := testContractCaller{outputs: map[string][]byte{
+		// stEthPerToken()
+		wstETH.Hex() + " 035faf82": mustPackABI(t, "uint256", new(big.Int).Mul(big.NewInt(118), big.NewInt(1e16))),
+		// balanceOf(address), whose selector is 0x70a08231
+		wstETH.Hex() + " 70a08231" + hex.EncodeToString(gethcommon.LeftPadBytes(token.Bytes(), 32)): mustPackABI(t, "uint256", big.NewInt(5e8)),
+		// getRate(address,uint32)
+		rateProvider.Hex() + " " + hex.EncodeToString(append(
+			evmCallSelector("getRate(address,uint32)"),
+			mustPackABI(t, "address,uint32", token, uint32(7))...,
+		)): mustPackABI(t, "uint64,int256", uint64(1), big.NewInt(4e8)),
+	}}
+	connect := func(network string, _ zerolog.Logger) (bind.ContractCaller, func(), error) {
+		if network != "ethereum" {
+			return nil, nil, fmt.Errorf("unexpected network %s", network)
+		}
+		return caller, func() {}, nil
+	}
+
+	config := json.RawMessage(`{"calls": {
+		"wsteth": {"network": "ethereum", "contract": "` + wstETH.Hex() + `", "method": "stEthPerToken()(uint256)", "decimals": 18},
+		"balance": {"network": "ethereum", "contract": "` + wstETH.Hex() + `", "method": "balanceOf(address)", "args": ["` + token.Hex() + `"], "decimals": 8},
+		"rate": {"network": "ethereum", "contract": "` + rateProvider.Hex() + `", "method": "getRate(address, uint32)(uint64, int256)", "args": ["` + token.Hex() + `", "7"], "return_index": 1, "decimals": 8, "invert": true},
+		"reverted": {"network": "ethereum", "contract": "` + rateProvider.Hex() + `", "method": "missing()"}
+	}}`)
+
+	t.Run("success", func(t *testing.T) {
+		prices, err := evmCallPriceUpdate(config, connect)(
+			set.New[types.Symbol]("wsteth", "balance", "rate", "reverted", "unconfigured"),
+			zerolog.New(io.Discard),
+		)
+		require.NoError(t, err)
+		require.Len(t, prices, 3)
+		require.InDelta(t, 1.18, prices["wsteth"], 1e-12)
+		require.InDelta(t, 5.0, prices["balance"], 1e-12)
+		require.InDelta(t, 0.25, prices["rate"], 1e-12)
+	})
+
+	t.Run("every call fails", func(t *testing.T) {
+		_, err := evmCallPriceUpdate(config, connect)(set.New[types.Symbol]("reverted"), zerolog.New(io.Discard))
+		require.ErrorContains(t, err, "execution reverted")
+	})
+}
+
+func evmCallSelector(method string) []byte {
+	return crypto.Keccak256([]byte(method))[:4]
+}
+
+func TestParseEVMCallConfig(t *testing.T) {
+	const contract = "0x7f39C581F595B53c5cb19bD0b3f8dA6c935E2Ca0"
+	testCases := []struct {
+		name    string
+		call    string
+		wantErr string
+	}{
+		{"valid", `{"network": "ethereum", "contract": "` + contract + `", "method": "stEthPerToken()"}`, ""},
+		{"unknown network", `{"network": "solana", "contract": "` + contract + `", "method": "stEthPerToken()"}`, "unsupported network: solana"},
+		{"invalid contract", `{"network": "ethereum", "contract": "0x123", "method": "stEthPerToken()"}`, "invalid contract address"},
+		{"invalid signature", `{"network": "ethereum", "contract": "` + contract + `", "method": "stEthPerToken"}`, "invalid method signature"},
+		{"unknown type", `{"network": "ethereum", "contract": "` + contract + `", "method": "rate()(float)"}`, "invalid method outputs"},
+		{"return index out of range", `{"network": "ethereum", "contract": "` + contract + `", "method": "rate()(uint256)", "return_index": 1}`, "out of range"},
+		{"non-integer return", `{"network": "ethereum", "contract": "` + contract + `", "method": "owner()(address)"}`, "is not an integer"},
+		{"missing argument", `{"network": "ethereum", "contract": "` + contract + `", "method": "balanceOf(address)"}`, "takes 1 arguments, got 0"},
+		{"invalid argument", `{"network": "ethereum", "contract": "` + contract + `", "method": "convert(uint8)", "args": ["256"]}`, "overflows uint8"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseEVMCallConfig(json.RawMessage(`{"calls": {"symbol": ` + tc.call + `}}`))
+			if tc.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestConvertFixedPointPrice(t *testing.T) {
+	for idx, tc := range []struct {
+		value    *big.Int
+		decimals uint8
+		want     float64
+	}{
+		{
+			value:    big.NewInt(5_000_000_000),
+			decimals: 8,
+			want:     50.0,
+		},
+		{
+			value: new(big.Int).Mul(
+				big.NewInt(420_690),
+				new(big.Int).Exp(big.NewInt(10), big.NewInt(15), nil),
+			),
+			decimals: 18,
+			want:     420.69,
+		},
+	} {
+		t.Run(fmt.Sprintf("tc %d - %+v", idx, tc), func(t *testing.T) {
+			gotPrice, err := convertFixedPointPrice(tc.value, tc.decimals)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, gotPrice)
+		})
+	}
+
+	_, err := convertFixedPointPrice(nil, 18)
+	require.ErrorContains(t, err, "value is nil")
+}
@@ -18,11 +18,11 @@ const (
 	SourceNameGateIo = "gateio"
 )
 
-var _ types.FetchPricesFunc = GateIoPriceUpdate
+var _ types.FetchRawPricesFunc = GateIoPriceUpdate
 
-// GateIoPriceUpdate returns the prices given the symbols or an error.
+// GateIoPriceUpdate returns the prices and 24h volumes given the symbols or an error.
 // Uses the GateIo API at https://www.gate.io/docs/developers/apiv4/en/#get-details-of-a-specifc-currency-pair.
-func GateIoPriceUpdate(symbols set.Set[types.Symbol], logger zerolog.Logger) (rawPrices map[types.Symbol]float64, err error) {
+func GateIoPriceUpdate(symbols set.Set[types.Symbol], logger zerolog.Logger) (rawPrices map[types.Symbol]types.RawPrice, err error) {
 	url := "https://api.gateio.ws/api/v4/spot/tickers"
 	resp, err := http.Get(url)
 	if err != nil {
@@ -54,7 +54,7 @@ func GateIoPriceUpdate(symbols set.Set[types.Symbol], logger zerolog.Logger) (ra
 		return nil, err
 	}
 
-	rawPrices = make(map[types.Symbol]float64)
+	rawPrices = make(map[types.Symbol]types.RawPrice)
 	for _, ticker := range tickers {
 		symbol := types.Symbol(ticker["currency_pair"].(string))
 		if !symbols.Has(symbol) {
@@ -66,8 +66,16 @@ func GateIoPriceUpdate(symbols set.Set[types.Symbol], logger zerolog.Logger) (ra
 			logger.Err(err).Msg(fmt.Sprintf("failed to parse price for %s on data source %s", symbol, SourceNameGateIo))
 			continue
 		}
+		var volume float64
+		if baseVolume, ok := ticker["base_volume"].(string); ok {
+			volume, err = strconv.ParseFloat(baseVolume, 64)
+			if err != nil {
+				logger.Err(err).Msg(fmt.Sprintf("failed to parse volume for %s on data source %s", symbol, SourceNameGateIo))
+				volume = 0
+			}
+		}
 
-		rawPrices[symbol] = price
+		rawPrices[symbol] = types.RawPrice{Price: price, Volume: volume}
 		logger.Debug().Msg(fmt.Sprintf("fetched price for %s on data source %s: %f", symbol, SourceNameGateIo, price))
 	}
 
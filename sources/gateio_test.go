@@ -16,7 +16,9 @@ func TestGateIoSource(t *testing.T) {
 		rawPrices, err := GateIoPriceUpdate(set.New[types.Symbol]("BTC_USDT", "ETH_USDT"), zerolog.New(io.Discard))
 		require.NoError(t, err)
 		require.Equal(t, 2, len(rawPrices))
-		require.NotZero(t, rawPrices["BTC_USDT"])
-		require.NotZero(t, rawPrices["ETH_USDT"])
+		require.NotZero(t, rawPrices["BTC_USDT"].Price)
+		require.NotZero(t, rawPrices["BTC_USDT"].Volume)
+		require.NotZero(t, rawPrices["ETH_USDT"].Price)
+		require.NotZero(t, rawPrices["ETH_USDT"].Volume)
 	})
 }
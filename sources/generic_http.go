@@ -0,0 +1,311 @@
+package sources
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/NibiruChain/nibiru/v2/x/common/set"
+	"github.com/rs/zerolog"
+
+	"github.com/NibiruChain/pricefeeder/metrics"
+	"github.com/NibiruChain/pricefeeder/types"
+)
+
+const (
+	SourceNameGenericHTTP = "generic_http"
+	// GenericHTTPInstanceSeparator separates the instance name in the source
+	// name of a named generic_http source, e.g. generic_http:kraken.
+	GenericHTTPInstanceSeparator = ":"
+
+	// GenericHTTPSymbolsPlaceholder is replaced in the URL by the requested
+	// symbols, sorted and joined by the symbols separator.
+	GenericHTTPSymbolsPlaceholder = "{symbols}"
+)
+
+// GenericHTTPConfig describes how to fetch and parse the tickers of a venue
+// that has no dedicated source.
+type GenericHTTPConfig struct {
+	// URL of the tickers endpoint. It may contain the {symbols} placeholder.
+	URL string `json:"url"`
+	// SymbolsSeparator joins the symbols replacing {symbols}. Defaults to ",".
+	SymbolsSeparator string `json:"symbols_separator"`
+	// Headers are added to the request, e.g. an API key.
+	Headers map[string]string `json:"headers"`
+	// TickersPath is the JSONPath to the tickers in the response: either an
+	// array of tickers, or an object whose keys are the symbols.
+	TickersPath string `json:"tickers_path"`
+	// SymbolPath is the JSONPath to the symbol, relative to a ticker. Required
+	// when the tickers are an array.
+	SymbolPath string `json:"symbol_path"`
+	// PricePath is the JSONPath to the price, relative to a ticker.
+	PricePath string `json:"price_path"`
+	// VolumePath is the optional JSONPath to the 24h base volume, relative to
+	// a ticker.
+	VolumePath string `json:"volume_path"`
+	// Decimals divides prices by 10^decimals, for venues reporting integers.
+	Decimals int `json:"decimals"`
+	// Invert reports the reciprocal of the price, for venues quoting the pair
+	// the other way around. The volume is converted to the quote asset.
+	Invert bool `json:"invert"`
+}
+
+// genericHTTPParser holds the parsed JSONPaths of a [GenericHTTPConfig].
+type genericHTTPParser struct {
+	config  GenericHTTPConfig
+	tickers jsonPath
+	symbol  jsonPath
+	price   jsonPath
+	volume  jsonPath
+}
+
+// IsGenericHTTPSource reports whether the source name is the generic_http
+// source or one of its named instances, e.g. generic_http:kraken. Each instance
+// is configured with its own entries in EXCHANGE_SYMBOLS_MAP and
+// DATASOURCE_CONFIG_MAP, so several venues can be added.
+func IsGenericHTTPSource(sourceName string) bool {
+	instance, ok := strings.CutPrefix(sourceName, SourceNameGenericHTTP+GenericHTTPInstanceSeparator)
+	return sourceName == SourceNameGenericHTTP || (ok && instance != "")
+}
+
+// genericHTTPSourceFactory returns the SourceFactory of the generic_http source
+// with the given name, which labels its logs and metrics.
+func genericHTTPSourceFactory(sourceName string) SourceFactory {
+	return func(
+		symbols set.Set[types.Symbol],
+		cfg json.RawMessage,
+		logger zerolog.Logger,
+	) types.Source {
+		return NewRawTickSource(symbols, GenericHTTPPriceUpdate(sourceName, cfg), logger)
+	}
+}
+
+// ValidateGenericHTTPConfig returns an error if the configuration of the
+// generic_http source is invalid.
+func ValidateGenericHTTPConfig(jsonConfig json.RawMessage) error {
+	_, err := newGenericHTTPParser(jsonConfig)
+	return err
+}
+
+func newGenericHTTPParser(jsonConfig json.RawMessage) (*genericHTTPParser, error) {
+	var config GenericHTTPConfig
+	if len(jsonConfig) > 0 {
+		if err := json.Unmarshal(jsonConfig, &config); err != nil {
+			return nil, fmt.Errorf("invalid generic_http config: %w", err)
+		}
+	}
+	if config.URL == "" {
+		return nil, fmt.Errorf("invalid generic_http config: no url")
+	}
+	if _, err := url.Parse(strings.ReplaceAll(config.URL, GenericHTTPSymbolsPlaceholder, "")); err != nil {
+		return nil, fmt.Errorf("invalid generic_http config: %w", err)
+	}
+	if config.PricePath == "" {
+		return nil, fmt.Errorf("invalid generic_http config: no price_path")
+	}
+	if config.Decimals < 0 {
+		return nil, fmt.Errorf("invalid generic_http config: decimals must not be negative: %d", config.Decimals)
+	}
+	if config.SymbolsSeparator == "" {
+		config.SymbolsSeparator = ","
+	}
+
+	p := &genericHTTPParser{config: config}
+	for _, field := range []struct {
+		name string
+		expr string
+		path *jsonPath
+	}{
+		{"tickers_path", config.TickersPath, &p.tickers},
+		{"symbol_path", config.SymbolPath, &p.symbol},
+		{"price_path", config.PricePath, &p.price},
+		{"volume_path", config.VolumePath, &p.volume},
+	} {
+		if field.expr == "" {
+			continue
+		}
+		path, err := parseJSONPath(field.expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid generic_http config: %s: %w", field.name, err)
+		}
+		*field.path = path
+	}
+	return p, nil
+}
+
+// GenericHTTPPriceUpdate returns the prices and 24h volumes given the symbols
+// or an error, fetched and parsed as described by the [GenericHTTPConfig].
+// The source name is either generic_http or one of its named instances.
+func GenericHTTPPriceUpdate(sourceName string, genericHTTPConfig json.RawMessage) types.FetchRawPricesFunc {
+	return func(symbols set.Set[types.Symbol], logger zerolog.Logger) (map[types.Symbol]types.RawPrice, error) {
+		parser, err := newGenericHTTPParser(genericHTTPConfig)
+		if err != nil {
+			logger.Err(err).Str("source", sourceName).Msg("failed to extract generic_http config")
+			metrics.PriceSourceCounter.WithLabelValues(sourceName, "false").Inc()
+			return nil, err
+		}
+
+		req, err := parser.buildRequest(symbols)
+		if err != nil {
+			logger.Err(err).Str("source", sourceName).Msg("failed to build request for generic_http")
+			metrics.PriceSourceCounter.WithLabelValues(sourceName, "false").Inc()
+			return nil, err
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			logger.Err(err).Str("source", sourceName).Msg("failed to fetch prices from generic_http")
+			metrics.PriceSourceCounter.WithLabelValues(sourceName, "false").Inc()
+			return nil, err
+		}
+		defer func() {
+			errClose := resp.Body.Close()
+			if errClose != nil {
+				errClose = fmt.Errorf("error closing response body: %w", errClose)
+				logger.Err(errClose).Str("source", sourceName).Msg(errClose.Error())
+			}
+		}()
+
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			logger.Err(err).Str("source", sourceName).Msg("failed to read response body from generic_http")
+			metrics.PriceSourceCounter.WithLabelValues(sourceName, "false").Inc()
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			err = fmt.Errorf("unexpected status %d: %s", resp.StatusCode, b)
+			logger.Err(err).Str("source", sourceName).Msg("failed to fetch prices from generic_http")
+			metrics.PriceSourceCounter.WithLabelValues(sourceName, "false").Inc()
+			return nil, err
+		}
+
+		rawPrices, err := parser.parse(b, symbols, sourceName, logger)
+		if err != nil {
+			logger.Err(err).Str("source", sourceName).Msg("failed to parse response body from generic_http")
+			metrics.PriceSourceCounter.WithLabelValues(sourceName, "false").Inc()
+			return nil, err
+		}
+
+		metrics.PriceSourceCounter.WithLabelValues(sourceName, "true").Inc()
+		return rawPrices, nil
+	}
+}
+
+func (p *genericHTTPParser) buildRequest(symbols set.Set[types.Symbol]) (*http.Request, error) {
+	symbolList := make([]string, 0, len(symbols))
+	for symbol := range symbols {
+		symbolList = append(symbolList, url.QueryEscape(string(symbol)))
+	}
+	sort.Strings(symbolList)
+	u := strings.ReplaceAll(p.config.URL, GenericHTTPSymbolsPlaceholder, strings.Join(symbolList, p.config.SymbolsSeparator))
+
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range p.config.Headers {
+		req.Header.Set(key, value)
+	}
+	return req, nil
+}
+
+// parse returns the prices and volumes of the symbols found in the response.
+func (p *genericHTTPParser) parse(b []byte, symbols set.Set[types.Symbol], sourceName string, logger zerolog.Logger) (map[types.Symbol]types.RawPrice, error) {
+	var doc any
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+	tickers, err := p.tickers.lookup(doc)
+	if err != nil {
+		return nil, fmt.Errorf("tickers_path: %w", err)
+	}
+
+	bySymbol := map[types.Symbol]any{}
+	switch tickers := tickers.(type) {
+	case map[string]any:
+		for symbol, ticker := range tickers {
+			bySymbol[types.Symbol(symbol)] = ticker
+		}
+	case []any:
+		if p.symbol == nil {
+			return nil, fmt.Errorf("symbol_path is required when the tickers are an array")
+		}
+		for _, ticker := range tickers {
+			symbol, err := p.symbol.lookup(ticker)
+			if err != nil {
+				logger.Err(err).Str("source", sourceName).Msg("failed to get ticker symbol")
+				continue
+			}
+			if s, ok := symbol.(string); ok {
+				bySymbol[types.Symbol(s)] = ticker
+			}
+		}
+	default:
+		return nil, fmt.Errorf("tickers_path: expected an array or object, got %T", tickers)
+	}
+
+	rawPrices := make(map[types.Symbol]types.RawPrice)
+	for symbol := range symbols {
+		ticker, ok := bySymbol[symbol]
+		if !ok {
+			logger.Error().Str("source", sourceName).Str("symbol", string(symbol)).Msg("symbol not found")
+			continue
+		}
+		price, volume, err := p.parseTicker(ticker)
+		if err != nil {
+			logger.Err(err).Msg(fmt.Sprintf("failed to parse price for %s on data source %s", symbol, sourceName))
+			continue
+		}
+
+		rawPrices[symbol] = types.RawPrice{Price: price, Volume: volume}
+		logger.Debug().Msg(fmt.Sprintf("fetched price for %s on data source %s: %f", symbol, sourceName, price))
+	}
+	return rawPrices, nil
+}
+
+// parseTicker returns the price and volume of a ticker, applying the numeric
+// parsing rules of the config.
+func (p *genericHTTPParser) parseTicker(ticker any) (price, volume float64, err error) {
+	v, err := p.price.lookup(ticker)
+	if err != nil {
+		return 0, 0, fmt.Errorf("price_path: %w", err)
+	}
+	price, err = parseJSONNumber(v)
+	if err != nil {
+		return 0, 0, fmt.Errorf("price_path: %w", err)
+	}
+	price /= math.Pow10(p.config.Decimals)
+	if price <= 0 {
+		return 0, 0, fmt.Errorf("non-positive price: %f", price)
+	}
+
+	if p.volume != nil {
+		if v, err := p.volume.lookup(ticker); err == nil {
+			volume, _ = parseJSONNumber(v)
+		}
+	}
+	if p.config.Invert {
+		// the base volume of the inverted pair is the quote volume of the pair
+		price, volume = 1/price, volume*price
+	}
+	return price, volume, nil
+}
+
+// parseJSONNumber returns the value of a JSON number, or of a string holding
+// a number as many venues report them.
+func parseJSONNumber(v any) (float64, error) {
+	switch v := v.(type) {
+	case float64:
+		return v, nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	default:
+		return 0, fmt.Errorf("expected a number, got %T", v)
+	}
+}
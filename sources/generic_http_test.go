@@ -0,0 +1,127 @@
+package sources
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NibiruChain/nibiru/v2/x/common/set"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NibiruChain/pricefeeder/types"
+)
+
+func TestGenericHTTPPriceUpdate(t *testing.T) {
+	var requests []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		switch r.URL.Path {
+		case "/array":
+			_, _ = w.Write([]byte(`{"retCode": 0, "result": {"list": [
+				{"symbol": "BTCUSDT", "lastPrice": "60000.5", "volume24h": "1234.5"},
+				{"symbol": "ETHUSDT", "lastPrice": 3000.25, "volume24h": "not-a-number"},
+				{"symbol": "DOGEUSDT", "lastPrice": "n/a"}
+			]}}`))
+		case "/object":
+			_, _ = w.Write([]byte(`{"error": [], "result": {
+				"XXBTZUSD": {"c": ["6000050", "0.1"], "v": ["1", "20"]}
+			}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Run("array of tickers", func(t *testing.T) {
+		requests = nil
+		config := `{
+			"url": "` + server.URL + `/array?symbols={symbols}",
+			"headers": {"X-Api-Key": "secret"},
+			"tickers_path": "$.result.list",
+			"symbol_path": "symbol",
+			"price_path": "lastPrice",
+			"volume_path": "volume24h"
+		}`
+		rawPrices, err := GenericHTTPPriceUpdate(SourceNameGenericHTTP, json.RawMessage(config))(
+			set.New[types.Symbol]("ETHUSDT", "BTCUSDT", "DOGEUSDT", "FOOUSDT"),
+			zerolog.New(io.Discard),
+		)
+		require.NoError(t, err)
+		require.Equal(t, map[types.Symbol]types.RawPrice{
+			"BTCUSDT": {Price: 60000.5, Volume: 1234.5},
+			"ETHUSDT": {Price: 3000.25},
+		}, rawPrices)
+
+		require.Len(t, requests, 1)
+		require.Equal(t, "BTCUSDT,DOGEUSDT,ETHUSDT,FOOUSDT", requests[0].URL.Query().Get("symbols"))
+		require.Equal(t, "secret", requests[0].Header.Get("X-Api-Key"))
+	})
+
+	t.Run("object of tickers with numeric rules", func(t *testing.T) {
+		config := `{
+			"url": "` + server.URL + `/object",
+			"tickers_path": "$.result",
+			"price_path": "c[0]",
+			"volume_path": "v[1]",
+			"decimals": 2,
+			"invert": true
+		}`
+		rawPrices, err := GenericHTTPPriceUpdate(SourceNameGenericHTTP, json.RawMessage(config))(set.New[types.Symbol]("XXBTZUSD"), zerolog.New(io.Discard))
+		require.NoError(t, err)
+		require.InDelta(t, 1/60000.5, rawPrices["XXBTZUSD"].Price, 1e-15)
+		require.InDelta(t, 20*60000.5, rawPrices["XXBTZUSD"].Volume, 1e-6)
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		config := `{"url": "` + server.URL + `/missing", "tickers_path": "$", "price_path": "price"}`
+		_, err := GenericHTTPPriceUpdate(SourceNameGenericHTTP, json.RawMessage(config))(set.New[types.Symbol]("BTCUSDT"), zerolog.New(io.Discard))
+		require.ErrorContains(t, err, "unexpected status 404")
+	})
+
+	t.Run("symbol path required for arrays", func(t *testing.T) {
+		config := `{"url": "` + server.URL + `/array", "tickers_path": "$.result.list", "price_path": "lastPrice"}`
+		_, err := GenericHTTPPriceUpdate(SourceNameGenericHTTP, json.RawMessage(config))(set.New[types.Symbol]("BTCUSDT"), zerolog.New(io.Discard))
+		require.ErrorContains(t, err, "symbol_path is required")
+	})
+}
+
+func TestValidateGenericHTTPConfig(t *testing.T) {
+	testCases := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{"valid", `{"url": "https://example.com/tickers", "tickers_path": "$.data", "symbol_path": "s", "price_path": "p"}`, ""},
+		{"no url", `{"price_path": "p"}`, "no url"},
+		{"no price path", `{"url": "https://example.com"}`, "no price_path"},
+		{"invalid path", `{"url": "https://example.com", "price_path": "$.data[*]"}`, "price_path: invalid JSONPath"},
+		{"negative decimals", `{"url": "https://example.com", "price_path": "p", "decimals": -1}`, "decimals must not be negative"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateGenericHTTPConfig(json.RawMessage(tc.config))
+			if tc.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestIsGenericHTTPSource(t *testing.T) {
+	require.True(t, IsGenericHTTPSource("generic_http"))
+	require.True(t, IsGenericHTTPSource("generic_http:kraken"))
+	require.False(t, IsGenericHTTPSource("generic_http:"))
+	require.False(t, IsGenericHTTPSource("generic_httpx"))
+	require.False(t, IsGenericHTTPSource(SourceNameBybit))
+
+	source, err := GetRegisteredSource("generic_http:kraken", set.New[types.Symbol]("XXBTZUSD"), nil, zerolog.New(io.Discard))
+	require.NoError(t, err)
+	source.Close()
+	_, err = GetRegisteredSource("generic_http:", set.New[types.Symbol]("XXBTZUSD"), nil, zerolog.New(io.Discard))
+	require.ErrorContains(t, err, "unknown data provider source name")
+}
@@ -0,0 +1,96 @@
+package sources
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/NibiruChain/nibiru/v2/x/common/set"
+	"github.com/rs/zerolog"
+
+	"github.com/NibiruChain/pricefeeder/metrics"
+	"github.com/NibiruChain/pricefeeder/types"
+)
+
+const (
+	SourceNameHtx = "htx"
+	HtxTickersURL = "https://api.huobi.pro/market/tickers"
+)
+
+var _ types.FetchRawPricesFunc = HtxPriceUpdate
+
+type HtxResponse struct {
+	Status string `json:"status"`
+	ErrMsg string `json:"err-msg"`
+	Data   []struct {
+		Symbol string  `json:"symbol"`
+		Close  float64 `json:"close"`
+		// Amount is the 24h volume in the base asset, Vol is in the quote asset.
+		Amount float64 `json:"amount"`
+	} `json:"data"`
+}
+
+// HtxPriceUpdate returns the prices and 24h volumes given the symbols or an error.
+// Symbols are lowercase, e.g. nibiusdt.
+// Uses the HTX (formerly Huobi) API at https://www.htx.com/en-us/opend/newApiPages/?id=7ec4a4da-7773-11ed-9966-0242ac110003.
+func HtxPriceUpdate(symbols set.Set[types.Symbol], logger zerolog.Logger) (rawPrices map[types.Symbol]types.RawPrice, err error) {
+	resp, err := http.Get(HtxTickersURL)
+	if err != nil {
+		logger.Err(err).Msg("failed to fetch prices from HTX")
+		metrics.PriceSourceCounter.WithLabelValues(SourceNameHtx, "false").Inc()
+		return nil, err
+	}
+	defer func() {
+		errClose := resp.Body.Close()
+		if errClose != nil {
+			errClose = fmt.Errorf("error closing response body: %w", errClose)
+			logger.Err(errClose).Str("source", SourceNameHtx).Msg(errClose.Error())
+		}
+	}()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		logger.Err(err).Msg("failed to read response body from HTX")
+		metrics.PriceSourceCounter.WithLabelValues(SourceNameHtx, "false").Inc()
+		return nil, err
+	}
+
+	rawPrices, err = parseHtxTickers(b, symbols, logger)
+	if err != nil {
+		logger.Err(err).Msg("failed to parse response body from HTX")
+		metrics.PriceSourceCounter.WithLabelValues(SourceNameHtx, "false").Inc()
+		return nil, err
+	}
+
+	metrics.PriceSourceCounter.WithLabelValues(SourceNameHtx, "true").Inc()
+	return rawPrices, nil
+}
+
+// parseHtxTickers returns the prices and 24h volumes of the symbols found in
+// the market tickers response body.
+func parseHtxTickers(b []byte, symbols set.Set[types.Symbol], logger zerolog.Logger) (map[types.Symbol]types.RawPrice, error) {
+	var response HtxResponse
+	if err := json.Unmarshal(b, &response); err != nil {
+		return nil, err
+	}
+	if response.Status != "ok" {
+		return nil, fmt.Errorf("htx returned status %s: %s", response.Status, response.ErrMsg)
+	}
+
+	rawPrices := make(map[types.Symbol]types.RawPrice)
+	for _, ticker := range response.Data {
+		symbol := types.Symbol(ticker.Symbol)
+		if !symbols.Has(symbol) {
+			continue
+		}
+		if ticker.Close <= 0 {
+			logger.Error().Str("source", SourceNameHtx).Str("symbol", ticker.Symbol).Msg("no last price")
+			continue
+		}
+
+		rawPrices[symbol] = types.RawPrice{Price: ticker.Close, Volume: ticker.Amount}
+		logger.Debug().Msg(fmt.Sprintf("fetched price for %s on data source %s: %f", symbol, SourceNameHtx, ticker.Close))
+	}
+	return rawPrices, nil
+}
@@ -0,0 +1,45 @@
+package sources
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/NibiruChain/nibiru/v2/x/common/set"
+	"github.com/jarcoal/httpmock"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NibiruChain/pricefeeder/types"
+)
+
+func TestHtxPriceUpdate(t *testing.T) {
+	payload, err := os.ReadFile("testdata/htx_market_tickers.json")
+	require.NoError(t, err)
+
+	t.Run("parse", func(t *testing.T) {
+		rawPrices, err := parseHtxTickers(payload, set.New[types.Symbol]("nibiusdt", "btcusdt", "foousdt"), zerolog.New(io.Discard))
+		require.NoError(t, err)
+		require.Equal(t, map[types.Symbol]types.RawPrice{
+			"nibiusdt": {Price: 0.02131, Volume: 1830211.55},
+			"btcusdt":  {Price: 67000.9, Volume: 3120.412094},
+		}, rawPrices)
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		errPayload, err := os.ReadFile("testdata/htx_error.json")
+		require.NoError(t, err)
+		_, err = parseHtxTickers(errPayload, set.New[types.Symbol]("nibiusdt"), zerolog.New(io.Discard))
+		require.ErrorContains(t, err, "invalid request")
+	})
+
+	t.Run("fetch", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		httpmock.RegisterResponder("GET", HtxTickersURL, httpmock.NewBytesResponder(200, payload))
+
+		rawPrices, err := HtxPriceUpdate(set.New[types.Symbol]("nibiusdt"), zerolog.New(io.Discard))
+		require.NoError(t, err)
+		require.Equal(t, 0.02131, rawPrices["nibiusdt"].Price)
+	})
+}
@@ -0,0 +1,94 @@
+package sources
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// jsonPath is a parsed JSONPath expression. Only the subset needed to reach a
+// single value is supported: the root ($), child fields (.name or ['name'])
+// and array indexes ([0]), e.g. $.result.list or $['data'][0].price.
+type jsonPath []jsonPathStep
+
+// jsonPathStep is either a field name or an array index.
+type jsonPathStep struct {
+	field   string
+	index   int
+	isIndex bool
+}
+
+// parseJSONPath parses the expression. The leading $ is optional, so that
+// paths relative to a ticker can be written as price or c[0].
+func parseJSONPath(expr string) (jsonPath, error) {
+	rest := strings.TrimPrefix(strings.TrimSpace(expr), "$")
+	var path jsonPath
+	for i := 0; len(rest) > 0; i++ {
+		switch {
+		case strings.HasPrefix(rest, "."):
+			rest = rest[1:]
+			fallthrough
+		case i == 0 && rest[0] != '[':
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("invalid JSONPath %q: empty field name", expr)
+			}
+			path = append(path, jsonPathStep{field: rest[:end]})
+			rest = rest[end:]
+		case strings.HasPrefix(rest, "["):
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("invalid JSONPath %q: unclosed bracket", expr)
+			}
+			inner := rest[1:end]
+			rest = rest[end+1:]
+			if unquoted, ok := unquoteJSONPathField(inner); ok {
+				path = append(path, jsonPathStep{field: unquoted})
+				continue
+			}
+			index, err := strconv.Atoi(inner)
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("invalid JSONPath %q: unsupported selector [%s]", expr, inner)
+			}
+			path = append(path, jsonPathStep{index: index, isIndex: true})
+		default:
+			return nil, fmt.Errorf("invalid JSONPath %q: unexpected %q", expr, rest)
+		}
+	}
+	return path, nil
+}
+
+func unquoteJSONPathField(s string) (string, bool) {
+	if len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1], true
+	}
+	return "", false
+}
+
+// lookup returns the value at the path in a document decoded into any.
+func (p jsonPath) lookup(v any) (any, error) {
+	for _, step := range p {
+		if step.isIndex {
+			array, ok := v.([]any)
+			if !ok {
+				return nil, fmt.Errorf("cannot index %T with [%d]", v, step.index)
+			}
+			if step.index >= len(array) {
+				return nil, fmt.Errorf("index [%d] out of range of %d elements", step.index, len(array))
+			}
+			v = array[step.index]
+			continue
+		}
+		object, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("cannot get field %q of %T", step.field, v)
+		}
+		if v, ok = object[step.field]; !ok {
+			return nil, fmt.Errorf("field %q not found", step.field)
+		}
+	}
+	return v, nil
+}
@@ -0,0 +1,46 @@
+package sources
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONPath(t *testing.T) {
+	var doc any
+	require.NoError(t, json.Unmarshal([]byte(`{"result": {"list": [{"symbol": "BTCUSDT", "c": ["60000.5", "0.1"]}], "a.b": 1}}`), &doc))
+
+	testCases := []struct {
+		expr    string
+		want    any
+		wantErr string
+	}{
+		{expr: "$", want: doc},
+		{expr: "$.result.list[0].symbol", want: "BTCUSDT"},
+		{expr: "result.list[0].c[0]", want: "60000.5"},
+		{expr: "$['result']['a.b']", want: float64(1)},
+		{expr: `$["result"].list[0]["symbol"]`, want: "BTCUSDT"},
+		{expr: "$.result.missing", wantErr: `field "missing" not found`},
+		{expr: "$.result.list[1]", wantErr: "out of range"},
+		{expr: "$.result[0]", wantErr: "cannot index"},
+		{expr: "$.result.list[*]", wantErr: "unsupported selector"},
+		{expr: "$.result..list", wantErr: "empty field name"},
+		{expr: "$.result.list[0", wantErr: "unclosed bracket"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.expr, func(t *testing.T) {
+			path, err := parseJSONPath(tc.expr)
+			if err == nil {
+				var got any
+				got, err = path.lookup(doc)
+				if tc.wantErr == "" {
+					require.NoError(t, err)
+					require.Equal(t, tc.want, got)
+					return
+				}
+			}
+			require.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package sources
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/NibiruChain/nibiru/v2/x/common/set"
+	"github.com/rs/zerolog"
+
+	"github.com/NibiruChain/pricefeeder/metrics"
+	"github.com/NibiruChain/pricefeeder/types"
+)
+
+const (
+	SourceNameKraken = "kraken"
+	KrakenTickerURL  = "https://api.kraken.com/0/public/Ticker"
+)
+
+var _ types.FetchRawPricesFunc = KrakenPriceUpdate
+
+// KrakenTicker is the ticker of a pair returned by the Kraken Ticker endpoint.
+// Every field is an array of strings, whose first element is the value.
+type KrakenTicker struct {
+	// LastTrade is the [price, lot volume] of the last trade.
+	LastTrade []string `json:"c"`
+	// Volume is the [today, last 24 hours] volume in the base asset.
+	Volume []string `json:"v"`
+}
+
+type KrakenResponse struct {
+	Error  []string                `json:"error"`
+	Result map[string]KrakenTicker `json:"result"`
+}
+
+// KrakenSymbolCsv returns the symbols sorted and comma-separated, as expected
+// by the pair query parameter.
+func KrakenSymbolCsv(symbols set.Set[types.Symbol]) string {
+	s := make([]string, 0, len(symbols))
+	for symbol := range symbols {
+		s = append(s, string(symbol))
+	}
+	sort.Strings(s)
+	return strings.Join(s, ",")
+}
+
+// KrakenPriceUpdate returns the prices and 24h volumes given the symbols or an error.
+// Symbols are the names Kraken uses in its responses, e.g. XXBTZUSD rather than XBTUSD.
+// Uses the Kraken API at https://docs.kraken.com/api/docs/rest-api/get-ticker-information.
+func KrakenPriceUpdate(symbols set.Set[types.Symbol], logger zerolog.Logger) (rawPrices map[types.Symbol]types.RawPrice, err error) {
+	url := KrakenTickerURL + "?pair=" + KrakenSymbolCsv(symbols)
+	resp, err := http.Get(url)
+	if err != nil {
+		logger.Err(err).Msg("failed to fetch prices from Kraken")
+		metrics.PriceSourceCounter.WithLabelValues(SourceNameKraken, "false").Inc()
+		return nil, err
+	}
+	defer func() {
+		errClose := resp.Body.Close()
+		if errClose != nil {
+			errClose = fmt.Errorf("error closing response body: %w", errClose)
+			logger.Err(errClose).Str("source", SourceNameKraken).Msg(errClose.Error())
+		}
+	}()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		logger.Err(err).Msg("failed to read response body from Kraken")
+		metrics.PriceSourceCounter.WithLabelValues(SourceNameKraken, "false").Inc()
+		return nil, err
+	}
+
+	var response KrakenResponse
+	err = json.Unmarshal(b, &response)
+	if err != nil {
+		logger.Err(err).Msg("failed to unmarshal response body from Kraken")
+		metrics.PriceSourceCounter.WithLabelValues(SourceNameKraken, "false").Inc()
+		return nil, err
+	}
+	if len(response.Error) > 0 {
+		err = fmt.Errorf("kraken returned errors: %s", strings.Join(response.Error, ", "))
+		logger.Err(err).Msg("failed to fetch prices from Kraken")
+		metrics.PriceSourceCounter.WithLabelValues(SourceNameKraken, "false").Inc()
+		return nil, err
+	}
+
+	rawPrices = make(map[types.Symbol]types.RawPrice)
+	for name, ticker := range response.Result {
+		symbol := types.Symbol(name)
+		if !symbols.Has(symbol) {
+			logger.Warn().Str("source", SourceNameKraken).Str("symbol", name).Msg("unexpected symbol, use the pair name returned by Kraken")
+			continue
+		}
+		if len(ticker.LastTrade) == 0 {
+			logger.Error().Str("source", SourceNameKraken).Str("symbol", name).Msg("missing last trade")
+			continue
+		}
+
+		price, err := strconv.ParseFloat(ticker.LastTrade[0], 64)
+		if err != nil {
+			logger.Err(err).Msg(fmt.Sprintf("failed to parse price for %s on data source %s", symbol, SourceNameKraken))
+			continue
+		}
+		var volume float64
+		if len(ticker.Volume) > 1 {
+			volume, err = strconv.ParseFloat(ticker.Volume[1], 64)
+			if err != nil {
+				logger.Err(err).Msg(fmt.Sprintf("failed to parse volume for %s on data source %s", symbol, SourceNameKraken))
+				volume = 0
+			}
+		}
+
+		rawPrices[symbol] = types.RawPrice{Price: price, Volume: volume}
+		logger.Debug().Msg(fmt.Sprintf("fetched price for %s on data source %s: %f", symbol, SourceNameKraken, price))
+	}
+
+	metrics.PriceSourceCounter.WithLabelValues(SourceNameKraken, "true").Inc()
+	return rawPrices, nil
+}
@@ -0,0 +1,68 @@
+package sources
+
+import (
+	"io"
+	"testing"
+
+	"github.com/NibiruChain/nibiru/v2/x/common/set"
+	"github.com/jarcoal/httpmock"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NibiruChain/pricefeeder/types"
+)
+
+func TestKrakenPriceUpdate(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	t.Run("success", func(t *testing.T) {
+		httpmock.Reset()
+		httpmock.RegisterResponder(
+			"GET", KrakenTickerURL+"?pair=XETHZUSD,XXBTZUSD",
+			httpmock.NewStringResponder(200, `{"error":[],"result":{
+				"XXBTZUSD":{"a":["60001.0","1","1.000"],"b":["60000.0","2","2.000"],"c":["60000.5","0.01"],"v":["100.5","1234.5"]},
+				"XETHZUSD":{"a":["3001.0","1","1.000"],"b":["3000.0","2","2.000"],"c":["3000.25","0.5"],"v":["2000","25000.75"]}
+			}}`),
+		)
+		rawPrices, err := KrakenPriceUpdate(set.New[types.Symbol]("XXBTZUSD", "XETHZUSD"), zerolog.New(io.Discard))
+		require.NoError(t, err)
+
+		require.Equal(t, 2, len(rawPrices))
+		require.Equal(t, 60000.5, rawPrices["XXBTZUSD"].Price)
+		require.Equal(t, 1234.5, rawPrices["XXBTZUSD"].Volume)
+		require.Equal(t, 3000.25, rawPrices["XETHZUSD"].Price)
+		require.Equal(t, 25000.75, rawPrices["XETHZUSD"].Volume)
+	})
+
+	t.Run("unknown pair", func(t *testing.T) {
+		httpmock.Reset()
+		httpmock.RegisterResponder(
+			"GET", KrakenTickerURL+"?pair=XBTUSDX",
+			httpmock.NewStringResponder(200, `{"error":["EQuery:Unknown asset pair"]}`),
+		)
+		_, err := KrakenPriceUpdate(set.New[types.Symbol]("XBTUSDX"), zerolog.New(io.Discard))
+		require.ErrorContains(t, err, "EQuery:Unknown asset pair")
+	})
+
+	t.Run("invalid price", func(t *testing.T) {
+		httpmock.Reset()
+		httpmock.RegisterResponder(
+			"GET", KrakenTickerURL+"?pair=XXBTZUSD",
+			httpmock.NewStringResponder(200, `{"error":[],"result":{"XXBTZUSD":{"c":["n/a","0.01"],"v":["1","2"]}}}`),
+		)
+		rawPrices, err := KrakenPriceUpdate(set.New[types.Symbol]("XXBTZUSD"), zerolog.New(io.Discard))
+		require.NoError(t, err)
+		require.Empty(t, rawPrices)
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		httpmock.Reset()
+		httpmock.RegisterResponder(
+			"GET", KrakenTickerURL+"?pair=XXBTZUSD",
+			httpmock.NewStringResponder(502, "bad gateway"),
+		)
+		_, err := KrakenPriceUpdate(set.New[types.Symbol]("XXBTZUSD"), zerolog.New(io.Discard))
+		require.Error(t, err)
+	})
+}
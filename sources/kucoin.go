@@ -0,0 +1,109 @@
+package sources
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/NibiruChain/nibiru/v2/x/common/set"
+	"github.com/rs/zerolog"
+
+	"github.com/NibiruChain/pricefeeder/metrics"
+	"github.com/NibiruChain/pricefeeder/types"
+)
+
+const (
+	SourceNameKucoin = "kucoin"
+	KucoinTickersURL = "https://api.kucoin.com/api/v1/market/allTickers"
+)
+
+var _ types.FetchRawPricesFunc = KucoinPriceUpdate
+
+type KucoinResponse struct {
+	Code string `json:"code"`
+	Msg  string `json:"msg"`
+	Data struct {
+		Ticker []struct {
+			Symbol string `json:"symbol"`
+			// Last is null for pairs without trades.
+			Last   *string `json:"last"`
+			Volume string  `json:"vol"`
+		} `json:"ticker"`
+	} `json:"data"`
+}
+
+// KucoinPriceUpdate returns the prices and 24h volumes given the symbols or an error.
+// Uses the KuCoin API at https://www.kucoin.com/docs/rest/spot-trading/market-data/get-all-tickers.
+func KucoinPriceUpdate(symbols set.Set[types.Symbol], logger zerolog.Logger) (rawPrices map[types.Symbol]types.RawPrice, err error) {
+	resp, err := http.Get(KucoinTickersURL)
+	if err != nil {
+		logger.Err(err).Msg("failed to fetch prices from KuCoin")
+		metrics.PriceSourceCounter.WithLabelValues(SourceNameKucoin, "false").Inc()
+		return nil, err
+	}
+	defer func() {
+		errClose := resp.Body.Close()
+		if errClose != nil {
+			errClose = fmt.Errorf("error closing response body: %w", errClose)
+			logger.Err(errClose).Str("source", SourceNameKucoin).Msg(errClose.Error())
+		}
+	}()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		logger.Err(err).Msg("failed to read response body from KuCoin")
+		metrics.PriceSourceCounter.WithLabelValues(SourceNameKucoin, "false").Inc()
+		return nil, err
+	}
+
+	rawPrices, err = parseKucoinTickers(b, symbols, logger)
+	if err != nil {
+		logger.Err(err).Msg("failed to parse response body from KuCoin")
+		metrics.PriceSourceCounter.WithLabelValues(SourceNameKucoin, "false").Inc()
+		return nil, err
+	}
+
+	metrics.PriceSourceCounter.WithLabelValues(SourceNameKucoin, "true").Inc()
+	return rawPrices, nil
+}
+
+// parseKucoinTickers returns the prices and 24h volumes of the symbols found in
+// the allTickers response body.
+func parseKucoinTickers(b []byte, symbols set.Set[types.Symbol], logger zerolog.Logger) (map[types.Symbol]types.RawPrice, error) {
+	var response KucoinResponse
+	if err := json.Unmarshal(b, &response); err != nil {
+		return nil, err
+	}
+	if response.Code != "200000" {
+		return nil, fmt.Errorf("kucoin returned code %s: %s", response.Code, response.Msg)
+	}
+
+	rawPrices := make(map[types.Symbol]types.RawPrice)
+	for _, ticker := range response.Data.Ticker {
+		symbol := types.Symbol(ticker.Symbol)
+		if !symbols.Has(symbol) {
+			continue
+		}
+		if ticker.Last == nil {
+			logger.Error().Str("source", SourceNameKucoin).Str("symbol", ticker.Symbol).Msg("no last price")
+			continue
+		}
+
+		price, err := strconv.ParseFloat(*ticker.Last, 64)
+		if err != nil {
+			logger.Err(err).Msg(fmt.Sprintf("failed to parse price for %s on data source %s", symbol, SourceNameKucoin))
+			continue
+		}
+		volume, err := strconv.ParseFloat(ticker.Volume, 64)
+		if err != nil {
+			logger.Err(err).Msg(fmt.Sprintf("failed to parse volume for %s on data source %s", symbol, SourceNameKucoin))
+			volume = 0
+		}
+
+		rawPrices[symbol] = types.RawPrice{Price: price, Volume: volume}
+		logger.Debug().Msg(fmt.Sprintf("fetched price for %s on data source %s: %f", symbol, SourceNameKucoin, price))
+	}
+	return rawPrices, nil
+}
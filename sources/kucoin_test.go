@@ -0,0 +1,43 @@
+package sources
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/NibiruChain/nibiru/v2/x/common/set"
+	"github.com/jarcoal/httpmock"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NibiruChain/pricefeeder/types"
+)
+
+func TestKucoinPriceUpdate(t *testing.T) {
+	payload, err := os.ReadFile("testdata/kucoin_all_tickers.json")
+	require.NoError(t, err)
+
+	t.Run("parse", func(t *testing.T) {
+		rawPrices, err := parseKucoinTickers(payload, set.New[types.Symbol]("NIBI-USDT", "BTC-USDT", "DEAD-USDT"), zerolog.New(io.Discard))
+		require.NoError(t, err)
+		require.Equal(t, map[types.Symbol]types.RawPrice{
+			"NIBI-USDT": {Price: 0.02132, Volume: 4128530.1257},
+			"BTC-USDT":  {Price: 67001.3, Volume: 2120.51694729},
+		}, rawPrices)
+	})
+
+	t.Run("error code", func(t *testing.T) {
+		_, err := parseKucoinTickers([]byte(`{"code":"429000","msg":"Too Many Requests"}`), set.New[types.Symbol]("NIBI-USDT"), zerolog.New(io.Discard))
+		require.ErrorContains(t, err, "Too Many Requests")
+	})
+
+	t.Run("fetch", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		httpmock.RegisterResponder("GET", KucoinTickersURL, httpmock.NewBytesResponder(200, payload))
+
+		rawPrices, err := KucoinPriceUpdate(set.New[types.Symbol]("NIBI-USDT"), zerolog.New(io.Discard))
+		require.NoError(t, err)
+		require.Equal(t, 0.02132, rawPrices["NIBI-USDT"].Price)
+	})
+}
@@ -0,0 +1,95 @@
+package sources
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/NibiruChain/nibiru/v2/x/common/set"
+	"github.com/rs/zerolog"
+
+	"github.com/NibiruChain/pricefeeder/metrics"
+	"github.com/NibiruChain/pricefeeder/types"
+)
+
+const (
+	SourceNameMexc = "mexc"
+	MexcTickersURL = "https://api.mexc.com/api/v3/ticker/24hr"
+)
+
+var _ types.FetchRawPricesFunc = MexcPriceUpdate
+
+type MexcTicker struct {
+	Symbol string `json:"symbol"`
+	Price  string `json:"lastPrice"`
+	Volume string `json:"volume"`
+}
+
+// MexcPriceUpdate returns the prices and 24h volumes given the symbols or an error.
+// Uses the MEXC API at https://mexcdevelop.github.io/apidocs/spot_v3_en/#24hr-ticker-price-change-statistics.
+func MexcPriceUpdate(symbols set.Set[types.Symbol], logger zerolog.Logger) (rawPrices map[types.Symbol]types.RawPrice, err error) {
+	resp, err := http.Get(MexcTickersURL)
+	if err != nil {
+		logger.Err(err).Msg("failed to fetch prices from MEXC")
+		metrics.PriceSourceCounter.WithLabelValues(SourceNameMexc, "false").Inc()
+		return nil, err
+	}
+	defer func() {
+		errClose := resp.Body.Close()
+		if errClose != nil {
+			errClose = fmt.Errorf("error closing response body: %w", errClose)
+			logger.Err(errClose).Str("source", SourceNameMexc).Msg(errClose.Error())
+		}
+	}()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		logger.Err(err).Msg("failed to read response body from MEXC")
+		metrics.PriceSourceCounter.WithLabelValues(SourceNameMexc, "false").Inc()
+		return nil, err
+	}
+
+	rawPrices, err = parseMexcTickers(b, symbols, logger)
+	if err != nil {
+		logger.Err(err).Msg("failed to parse response body from MEXC")
+		metrics.PriceSourceCounter.WithLabelValues(SourceNameMexc, "false").Inc()
+		return nil, err
+	}
+
+	metrics.PriceSourceCounter.WithLabelValues(SourceNameMexc, "true").Inc()
+	return rawPrices, nil
+}
+
+// parseMexcTickers returns the prices and 24h volumes of the symbols found in
+// the 24hr ticker response body.
+func parseMexcTickers(b []byte, symbols set.Set[types.Symbol], logger zerolog.Logger) (map[types.Symbol]types.RawPrice, error) {
+	var tickers []MexcTicker
+	if err := json.Unmarshal(b, &tickers); err != nil {
+		return nil, err
+	}
+
+	rawPrices := make(map[types.Symbol]types.RawPrice)
+	for _, ticker := range tickers {
+		symbol := types.Symbol(ticker.Symbol)
+		if !symbols.Has(symbol) {
+			continue
+		}
+
+		price, err := strconv.ParseFloat(ticker.Price, 64)
+		if err != nil {
+			logger.Err(err).Msg(fmt.Sprintf("failed to parse price for %s on data source %s", symbol, SourceNameMexc))
+			continue
+		}
+		volume, err := strconv.ParseFloat(ticker.Volume, 64)
+		if err != nil {
+			logger.Err(err).Msg(fmt.Sprintf("failed to parse volume for %s on data source %s", symbol, SourceNameMexc))
+			volume = 0
+		}
+
+		rawPrices[symbol] = types.RawPrice{Price: price, Volume: volume}
+		logger.Debug().Msg(fmt.Sprintf("fetched price for %s on data source %s: %f", symbol, SourceNameMexc, price))
+	}
+	return rawPrices, nil
+}
@@ -0,0 +1,43 @@
+package sources
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/NibiruChain/nibiru/v2/x/common/set"
+	"github.com/jarcoal/httpmock"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NibiruChain/pricefeeder/types"
+)
+
+func TestMexcPriceUpdate(t *testing.T) {
+	payload, err := os.ReadFile("testdata/mexc_ticker_24hr.json")
+	require.NoError(t, err)
+
+	t.Run("parse", func(t *testing.T) {
+		rawPrices, err := parseMexcTickers(payload, set.New[types.Symbol]("NIBIUSDT", "BTCUSDT", "FOOUSDT"), zerolog.New(io.Discard))
+		require.NoError(t, err)
+		require.Equal(t, map[types.Symbol]types.RawPrice{
+			"NIBIUSDT": {Price: 0.02133, Volume: 2301044.82},
+			"BTCUSDT":  {Price: 67001.31, Volume: 1523.85},
+		}, rawPrices)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, err := parseMexcTickers([]byte(`{"code":-1121,"msg":"Invalid symbol."}`), set.New[types.Symbol]("NIBIUSDT"), zerolog.New(io.Discard))
+		require.Error(t, err)
+	})
+
+	t.Run("fetch", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		httpmock.RegisterResponder("GET", MexcTickersURL, httpmock.NewBytesResponder(200, payload))
+
+		rawPrices, err := MexcPriceUpdate(set.New[types.Symbol]("NIBIUSDT"), zerolog.New(io.Discard))
+		require.NoError(t, err)
+		require.Equal(t, 0.02133, rawPrices["NIBIUSDT"].Price)
+	})
+}
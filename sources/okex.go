@@ -18,20 +18,21 @@ const (
 	SourceNameOkex = "okex"
 )
 
-var _ types.FetchPricesFunc = OkexPriceUpdate
+var _ types.FetchRawPricesFunc = OkexPriceUpdate
 
 type OkexTicker struct {
 	Symbol string `json:"instId"`
 	Price  string `json:"last"`
+	Volume string `json:"vol24h"`
 }
 
 type OkexResponse struct {
 	Data []OkexTicker `json:"data"`
 }
 
-// OkexPriceUpdate returns the prices for given symbols or an error.
+// OkexPriceUpdate returns the prices and 24h volumes for given symbols or an error.
 // Uses OKEX API at https://www.okx.com/docs-v5/en/#rest-api-market-data.
-func OkexPriceUpdate(symbols set.Set[types.Symbol], logger zerolog.Logger) (rawPrices map[types.Symbol]float64, err error) {
+func OkexPriceUpdate(symbols set.Set[types.Symbol], logger zerolog.Logger) (rawPrices map[types.Symbol]types.RawPrice, err error) {
 	url := "https://www.okx.com/api/v5/market/tickers?instType=SPOT"
 
 	resp, err := http.Get(url)
@@ -64,7 +65,7 @@ func OkexPriceUpdate(symbols set.Set[types.Symbol], logger zerolog.Logger) (rawP
 		return nil, err
 	}
 
-	rawPrices = make(map[types.Symbol]float64)
+	rawPrices = make(map[types.Symbol]types.RawPrice)
 	for _, ticker := range response.Data {
 		symbol := types.Symbol(ticker.Symbol)
 		if !symbols.Has(symbol) {
@@ -76,8 +77,13 @@ func OkexPriceUpdate(symbols set.Set[types.Symbol], logger zerolog.Logger) (rawP
 			logger.Err(err).Msg(fmt.Sprintf("failed to parse price for %s on data source %s", symbol, SourceNameOkex))
 			continue
 		}
+		volume, err := strconv.ParseFloat(ticker.Volume, 64)
+		if err != nil {
+			logger.Err(err).Msg(fmt.Sprintf("failed to parse volume for %s on data source %s", symbol, SourceNameOkex))
+			volume = 0
+		}
 
-		rawPrices[symbol] = price
+		rawPrices[symbol] = types.RawPrice{Price: price, Volume: volume}
 		logger.Debug().Msg(fmt.Sprintf("fetched price for %s on data source %s: %f", symbol, SourceNameOkex, price))
 	}
 
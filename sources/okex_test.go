@@ -16,7 +16,9 @@ func TestOKexPriceUpdate(t *testing.T) {
 		rawPrices, err := OkexPriceUpdate(set.New[types.Symbol]("BTC-USDT", "ETH-USDT"), zerolog.New(io.Discard))
 		require.NoError(t, err)
 		require.Equal(t, 2, len(rawPrices))
-		require.NotZero(t, rawPrices["BTC-USDT"])
-		require.NotZero(t, rawPrices["ETH-USDT"])
+		require.NotZero(t, rawPrices["BTC-USDT"].Price)
+		require.NotZero(t, rawPrices["BTC-USDT"].Volume)
+		require.NotZero(t, rawPrices["ETH-USDT"].Price)
+		require.NotZero(t, rawPrices["ETH-USDT"].Volume)
 	})
 }
@@ -0,0 +1,189 @@
+package sources
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NibiruChain/nibiru/v2/x/common/set"
+	"github.com/rs/zerolog"
+
+	"github.com/NibiruChain/pricefeeder/metrics"
+	"github.com/NibiruChain/pricefeeder/types"
+)
+
+const (
+	SourceNamePyth = "pyth"
+
+	// PythDefaultEndpoint is the public Hermes endpoint of the Pyth network.
+	PythDefaultEndpoint = "https://hermes.pyth.network"
+	// PythDefaultMaxConfRatio rejects prices whose confidence interval is
+	// wider than 1% of the price.
+	PythDefaultMaxConfRatio = 0.01
+)
+
+type PythConfig struct {
+	// Endpoint is the base URL of the Hermes HTTP API.
+	Endpoint string `json:"endpoint"`
+	// MaxConfRatio rejects prices whose confidence interval divided by the
+	// price exceeds this ratio.
+	MaxConfRatio float64 `json:"max_conf_ratio"`
+}
+
+type PythPrice struct {
+	Price       string `json:"price"`
+	Conf        string `json:"conf"`
+	Expo        int    `json:"expo"`
+	PublishTime int64  `json:"publish_time"`
+}
+
+type PythResponse struct {
+	Parsed []struct {
+		ID    string    `json:"id"`
+		Price PythPrice `json:"price"`
+	} `json:"parsed"`
+}
+
+// PythPriceUpdate returns the prices of the Pyth feeds given their IDs as
+// symbols, e.g. 0xe62df6c8b4a85fe1a67db44dc12de5db330f7ac66b72dc658afedf0f4a415b43
+// for BTC/USD. Prices whose confidence interval is too wide are skipped, and
+// the update time of each price is its publish time.
+// Uses the Hermes API at https://hermes.pyth.network/docs/#/rest/latest_price_updates.
+func PythPriceUpdate(pythConfig json.RawMessage) types.FetchRawPricesFunc {
+	return func(symbols set.Set[types.Symbol], logger zerolog.Logger) (map[types.Symbol]types.RawPrice, error) {
+		config, err := ParsePythConfig(pythConfig)
+		if err != nil {
+			logger.Err(err).Msg("failed to extract pyth config")
+			metrics.PriceSourceCounter.WithLabelValues(SourceNamePyth, "false").Inc()
+			return nil, err
+		}
+
+		params := url.Values{}
+		params.Set("parsed", "true")
+		params.Set("encoding", "hex")
+		feedIDToSymbol := make(map[string]types.Symbol, len(symbols))
+		for symbol := range symbols {
+			feedID := normalizePythFeedID(string(symbol))
+			feedIDToSymbol[feedID] = symbol
+			params.Add("ids[]", feedID)
+		}
+
+		resp, err := http.Get(strings.TrimSuffix(config.Endpoint, "/") + "/v2/updates/price/latest?" + params.Encode())
+		if err != nil {
+			logger.Err(err).Msg("failed to fetch prices from Pyth")
+			metrics.PriceSourceCounter.WithLabelValues(SourceNamePyth, "false").Inc()
+			return nil, err
+		}
+		defer func() {
+			errClose := resp.Body.Close()
+			if errClose != nil {
+				errClose = fmt.Errorf("error closing response body: %w", errClose)
+				logger.Err(errClose).Str("source", SourceNamePyth).Msg(errClose.Error())
+			}
+		}()
+
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			logger.Err(err).Msg("failed to read response body from Pyth")
+			metrics.PriceSourceCounter.WithLabelValues(SourceNamePyth, "false").Inc()
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			err = fmt.Errorf("unexpected status %d: %s", resp.StatusCode, b)
+			logger.Err(err).Msg("failed to fetch prices from Pyth")
+			metrics.PriceSourceCounter.WithLabelValues(SourceNamePyth, "false").Inc()
+			return nil, err
+		}
+
+		var response PythResponse
+		if err := json.Unmarshal(b, &response); err != nil {
+			logger.Err(err).Msg("failed to unmarshal response body from Pyth")
+			metrics.PriceSourceCounter.WithLabelValues(SourceNamePyth, "false").Inc()
+			return nil, err
+		}
+
+		rawPrices := make(map[types.Symbol]types.RawPrice)
+		for _, update := range response.Parsed {
+			symbol, ok := feedIDToSymbol[normalizePythFeedID(update.ID)]
+			if !ok {
+				continue
+			}
+			price, conf, err := parsePythPrice(update.Price)
+			if err != nil {
+				logger.Err(err).Msg(fmt.Sprintf("failed to parse price for %s on data source %s", symbol, SourceNamePyth))
+				continue
+			}
+			if price <= 0 {
+				logger.Error().Str("source", SourceNamePyth).Str("symbol", string(symbol)).Float64("price", price).Msg("non-positive price")
+				continue
+			}
+			if confRatio := conf / price; confRatio > config.MaxConfRatio {
+				logger.Warn().
+					Str("source", SourceNamePyth).
+					Str("symbol", string(symbol)).
+					Float64("price", price).
+					Float64("conf", conf).
+					Float64("max_conf_ratio", config.MaxConfRatio).
+					Msg("confidence interval too wide, skipping price")
+				continue
+			}
+
+			rawPrices[symbol] = types.RawPrice{Price: price, UpdateTime: time.Unix(update.Price.PublishTime, 0)}
+			logger.Debug().Msg(fmt.Sprintf("fetched price for %s on data source %s: %f", symbol, SourceNamePyth, price))
+		}
+
+		metrics.PriceSourceCounter.WithLabelValues(SourceNamePyth, "true").Inc()
+		return rawPrices, nil
+	}
+}
+
+// ParsePythConfig parses and validates the configuration of the pyth source,
+// using the defaults for the fields that are not set.
+func ParsePythConfig(jsonConfig json.RawMessage) (PythConfig, error) {
+	var c PythConfig
+	if len(jsonConfig) > 0 {
+		if err := json.Unmarshal(jsonConfig, &c); err != nil {
+			return c, fmt.Errorf("invalid pyth config: %w", err)
+		}
+	}
+	if c.Endpoint == "" {
+		c.Endpoint = PythDefaultEndpoint
+	}
+	if u, err := url.Parse(c.Endpoint); err != nil || u.Scheme == "" || u.Host == "" {
+		return c, fmt.Errorf("invalid pyth config: invalid endpoint: %q", c.Endpoint)
+	}
+	if c.MaxConfRatio == 0 {
+		c.MaxConfRatio = PythDefaultMaxConfRatio
+	}
+	if c.MaxConfRatio < 0 {
+		return c, fmt.Errorf("invalid pyth config: max_conf_ratio must not be negative: %f", c.MaxConfRatio)
+	}
+	return c, nil
+}
+
+// normalizePythFeedID returns the feed ID in lowercase without the 0x prefix,
+// as returned by Hermes.
+func normalizePythFeedID(feedID string) string {
+	return strings.TrimPrefix(strings.ToLower(feedID), "0x")
+}
+
+// parsePythPrice returns the price and confidence interval scaled by the
+// exponent of the price.
+func parsePythPrice(p PythPrice) (price, conf float64, err error) {
+	price, err = strconv.ParseFloat(p.Price, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	conf, err = strconv.ParseFloat(p.Conf, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	scale := math.Pow10(p.Expo)
+	return price * scale, conf * scale, nil
+}
@@ -0,0 +1,68 @@
+package sources
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/NibiruChain/nibiru/v2/x/common/set"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NibiruChain/pricefeeder/types"
+)
+
+const (
+	pythBTCFeedID = "0xe62df6c8b4a85fe1a67db44dc12de5db330f7ac66b72dc658afedf0f4a415b43"
+	pythETHFeedID = "0xff61491a931112ddf1bd8147cd1b641375f79f5825126d665480874634fd0ace"
+)
+
+func TestPythPriceUpdate(t *testing.T) {
+	var path string
+	var query map[string][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, query = r.URL.Path, r.URL.Query()
+		_, _ = w.Write([]byte(`{
+			"binary": {"encoding": "hex", "data": []},
+			"parsed": [
+				{"id": "e62df6c8b4a85fe1a67db44dc12de5db330f7ac66b72dc658afedf0f4a415b43", "price": {"price": "6700012345678", "conf": "2012345678", "expo": -8, "publish_time": 1718000000}},
+				{"id": "ff61491a931112ddf1bd8147cd1b641375f79f5825126d665480874634fd0ace", "price": {"price": "300025000000", "conf": "6000000000", "expo": -8, "publish_time": 1718000001}}
+			]
+		}`))
+	}))
+	defer server.Close()
+
+	symbols := set.New[types.Symbol](pythBTCFeedID, pythETHFeedID)
+
+	t.Run("success", func(t *testing.T) {
+		rawPrices, err := PythPriceUpdate(json.RawMessage(`{"endpoint": "`+server.URL+`", "max_conf_ratio": 0.05}`))(symbols, zerolog.New(io.Discard))
+		require.NoError(t, err)
+		require.Equal(t, "/v2/updates/price/latest", path)
+		require.ElementsMatch(t, []string{
+			"e62df6c8b4a85fe1a67db44dc12de5db330f7ac66b72dc658afedf0f4a415b43",
+			"ff61491a931112ddf1bd8147cd1b641375f79f5825126d665480874634fd0ace",
+		}, query["ids[]"])
+
+		require.Equal(t, 2, len(rawPrices))
+		require.InDelta(t, 67000.12345678, rawPrices[pythBTCFeedID].Price, 1e-9)
+		require.Equal(t, time.Unix(1718000000, 0), rawPrices[pythBTCFeedID].UpdateTime)
+		require.InDelta(t, 3000.25, rawPrices[pythETHFeedID].Price, 1e-9)
+		require.Equal(t, time.Unix(1718000001, 0), rawPrices[pythETHFeedID].UpdateTime)
+	})
+
+	t.Run("confidence interval too wide", func(t *testing.T) {
+		// ETH conf/price is 2%, BTC conf/price is 0.03%
+		rawPrices, err := PythPriceUpdate(json.RawMessage(`{"endpoint": "`+server.URL+`"}`))(symbols, zerolog.New(io.Discard))
+		require.NoError(t, err)
+		require.Contains(t, rawPrices, types.Symbol(pythBTCFeedID))
+		require.NotContains(t, rawPrices, types.Symbol(pythETHFeedID))
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := PythPriceUpdate(json.RawMessage(`{"max_conf_ratio": -1}`))(symbols, zerolog.New(io.Discard))
+		require.ErrorContains(t, err, "max_conf_ratio must not be negative")
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/NibiruChain/nibiru/v2/x/common/set"
 	"github.com/rs/zerolog"
@@ -64,6 +65,9 @@ func GetRegisteredSource(
 	muSource.RLock()
 	sourceFactory, ok := sourceRegistry[name]
 	muSource.RUnlock()
+	if !ok && IsGenericHTTPSource(name) {
+		sourceFactory, ok = genericHTTPSourceFactory(name), true
+	}
 	if !ok {
 		return nil, fmt.Errorf("unknown data provider source name: %s", name)
 	} else if sourceFactory == nil {
@@ -72,6 +76,23 @@ func GetRegisteredSource(
 	return sourceFactory(symbols, cfg, logger), nil
 }
 
+// defaultMaxPriceAges holds the default max price age of the sources whose
+// prices are reported with their on-chain update or last trade time, which is
+// usually older than [types.PriceTimeout].
+var defaultMaxPriceAges = map[string]time.Duration{
+	SourceNameChainLink: chainlinkMaxPriceAge,
+	SourceNameCoinbase:  coinbaseMaxPriceAge,
+}
+
+// DefaultMaxPriceAge returns the max price age of the prices of a source when
+// none is configured with max_price_age in DATASOURCE_CONFIG_MAP.
+func DefaultMaxPriceAge(name string) time.Duration {
+	if maxPriceAge, ok := defaultMaxPriceAges[name]; ok {
+		return maxPriceAge
+	}
+	return types.PriceTimeout
+}
+
 func init() {
 	for _, namedSource := range allSources {
 		Register(namedSource)
@@ -106,7 +127,7 @@ var allSources = []NamedSource{
 			_ json.RawMessage,
 			logger zerolog.Logger,
 		) types.Source {
-			return NewTickSource(symbols, BitfinexPriceUpdate, logger)
+			return NewRawTickSource(symbols, BitfinexPriceUpdate, logger)
 		},
 	},
 	{
@@ -116,7 +137,7 @@ var allSources = []NamedSource{
 			_ json.RawMessage,
 			logger zerolog.Logger,
 		) types.Source {
-			return NewTickSource(symbols, OkexPriceUpdate, logger)
+			return NewRawTickSource(symbols, OkexPriceUpdate, logger)
 		},
 	},
 	{
@@ -126,7 +147,101 @@ var allSources = []NamedSource{
 			_ json.RawMessage,
 			logger zerolog.Logger,
 		) types.Source {
-			return NewTickSource(symbols, GateIoPriceUpdate, logger)
+			return NewRawTickSource(symbols, GateIoPriceUpdate, logger)
+		},
+	},
+	{
+		Name: SourceNameKraken,
+		F: func(
+			symbols set.Set[types.Symbol],
+			_ json.RawMessage,
+			logger zerolog.Logger,
+		) types.Source {
+			return NewRawTickSource(symbols, KrakenPriceUpdate, logger)
+		},
+	},
+	{
+		Name: SourceNameCoinbase,
+		F: func(
+			symbols set.Set[types.Symbol],
+			_ json.RawMessage,
+			logger zerolog.Logger,
+		) types.Source {
+			return NewRawTickSource(symbols, CoinbasePriceUpdate, logger)
+		},
+	},
+	{
+		Name: SourceNameKucoin,
+		F: func(
+			symbols set.Set[types.Symbol],
+			_ json.RawMessage,
+			logger zerolog.Logger,
+		) types.Source {
+			return NewRawTickSource(symbols, KucoinPriceUpdate, logger)
+		},
+	},
+	{
+		Name: SourceNameMexc,
+		F: func(
+			symbols set.Set[types.Symbol],
+			_ json.RawMessage,
+			logger zerolog.Logger,
+		) types.Source {
+			return NewRawTickSource(symbols, MexcPriceUpdate, logger)
+		},
+	},
+	{
+		Name: SourceNameHtx,
+		F: func(
+			symbols set.Set[types.Symbol],
+			_ json.RawMessage,
+			logger zerolog.Logger,
+		) types.Source {
+			return NewRawTickSource(symbols, HtxPriceUpdate, logger)
+		},
+	},
+	{
+		Name: SourceNamePyth,
+		F: func(
+			symbols set.Set[types.Symbol],
+			cfg json.RawMessage,
+			logger zerolog.Logger,
+		) types.Source {
+			return NewRawTickSource(symbols, PythPriceUpdate(cfg), logger)
+		},
+	},
+	{
+		Name: SourceNameGenericHTTP,
+		F:    genericHTTPSourceFactory(SourceNameGenericHTTP),
+	},
+	{
+		Name: SourceNameCosmWasmQuery,
+		F: func(
+			symbols set.Set[types.Symbol],
+			cfg json.RawMessage,
+			logger zerolog.Logger,
+		) types.Source {
+			return NewTickSource(symbols, CosmWasmQueryPriceUpdate(cfg), logger)
+		},
+	},
+	{
+		Name: SourceNameEVMCall,
+		F: func(
+			symbols set.Set[types.Symbol],
+			cfg json.RawMessage,
+			logger zerolog.Logger,
+		) types.Source {
+			return NewTickSource(symbols, EVMCallPriceUpdate(cfg), logger)
+		},
+	},
+	{
+		Name: SourceNameERC4626,
+		F: func(
+			symbols set.Set[types.Symbol],
+			cfg json.RawMessage,
+			logger zerolog.Logger,
+		) types.Source {
+			return NewTickSource(symbols, ERC4626PriceUpdate(cfg), logger)
 		},
 	},
 	{
@@ -146,7 +261,7 @@ var allSources = []NamedSource{
 			_ json.RawMessage,
 			logger zerolog.Logger,
 		) types.Source {
-			return NewTickSource(symbols, BybitPriceUpdate, logger)
+			return NewRawTickSource(symbols, BybitPriceUpdate, logger)
 		},
 	},
 	{
@@ -183,10 +298,10 @@ var allSources = []NamedSource{
 		Name: SourceNameChainLink,
 		F: func(
 			symbols set.Set[types.Symbol],
-			_ json.RawMessage,
+			cfg json.RawMessage,
 			logger zerolog.Logger,
 		) types.Source {
-			return NewTickSource(symbols, ChainlinkPriceUpdate, logger)
+			return NewRawTickSource(symbols, ChainlinkPriceUpdate(cfg), logger)
 		},
 	},
 }
@@ -26,6 +26,28 @@ func NewTickSource(
 	symbols set.Set[types.Symbol],
 	fetchPricesFunc types.FetchPricesFunc,
 	logger zerolog.Logger,
+) *TickSource {
+	fetchRawPrices := func(symbols set.Set[types.Symbol], logger zerolog.Logger) (map[types.Symbol]types.RawPrice, error) {
+		prices, err := fetchPricesFunc(symbols, logger)
+		if err != nil {
+			return nil, err
+		}
+		rawPrices := make(map[types.Symbol]types.RawPrice, len(prices))
+		for symbol, price := range prices {
+			rawPrices[symbol] = types.RawPrice{Price: price}
+		}
+		return rawPrices, nil
+	}
+	return NewRawTickSource(symbols, fetchRawPrices, logger)
+}
+
+// NewRawTickSource instantiates a new [TickSource] instance, given the symbols
+// and a price updater function which returns the latest [types.RawPrice] for
+// the provided symbols, for sources reporting more than the price.
+func NewRawTickSource(
+	symbols set.Set[types.Symbol],
+	fetchRawPricesFunc types.FetchRawPricesFunc,
+	logger zerolog.Logger,
 ) *TickSource {
 	ts := &TickSource{
 		logger:             logger,
@@ -33,7 +55,7 @@ func NewTickSource(
 		done:               make(chan struct{}),
 		tick:               time.NewTicker(UpdateTick),
 		symbols:            symbols,
-		fetchPrices:        fetchPricesFunc,
+		fetchPrices:        fetchRawPricesFunc,
 		priceUpdateChannel: make(chan map[types.Symbol]types.RawPrice),
 	}
 
@@ -49,13 +71,14 @@ type TickSource struct {
 	done               chan struct{} // internal signal to wait for shutdown operations
 	tick               *time.Ticker
 	symbols            set.Set[types.Symbol] // symbols as named on the third party data source
-	fetchPrices        func(symbols set.Set[types.Symbol], logger zerolog.Logger) (map[types.Symbol]float64, error)
+	fetchPrices        types.FetchRawPricesFunc
 	priceUpdateChannel chan map[types.Symbol]types.RawPrice
 }
 
 // loop runs in a background goroutine and periodically fetches prices at the
 // interval defined by UpdateTick. When prices are received, they are formatted
-// as RawPrice objects and sent on the priceUpdateChannel. The loop handles
+// as RawPrice objects and sent on the priceUpdateChannel. A failed fetch is
+// sent as an empty update. The loop handles
 // shutdown gracefully by dropping pending updates if a stop signal is received
 // while trying to send.
 func (s *TickSource) loop() {
@@ -71,16 +94,18 @@ func (s *TickSource) loop() {
 
 			rawPrices, err := s.fetchPrices(s.symbols, s.logger)
 			if err != nil {
+				// an empty update reports the failed fetch
 				s.logger.Err(err).Msg("failed to update prices")
-				break // breaks the current select case, not the for cycle
+				rawPrices = nil
 			}
 
+			now := time.Now()
 			priceUpdate := make(map[types.Symbol]types.RawPrice, len(rawPrices))
 			for symbol, price := range rawPrices {
-				priceUpdate[symbol] = types.RawPrice{
-					Price:      price,
-					UpdateTime: time.Now(),
+				if price.UpdateTime.IsZero() {
+					price.UpdateTime = now
 				}
+				priceUpdate[symbol] = price
 			}
 
 			s.logger.Debug().Msg("sending price update")
@@ -60,6 +60,32 @@ func TestTickSource(t *testing.T) {
 		}
 	})
 
+	t.Run("raw prices", func(t *testing.T) {
+		updateTime := time.Now().Add(-time.Minute)
+		expectedPrices := map[types.Symbol]types.RawPrice{
+			"tBTCUSD": {Price: 250_000.56, Volume: 1_234.5},
+			"tETHUSD": {Price: 10_000.1, UpdateTime: updateTime},
+		}
+
+		ts := NewRawTickSource(set.New[types.Symbol]("tBTCUSD", "tETHUSD"),
+			func(symbols set.Set[types.Symbol], logger zerolog.Logger) (map[types.Symbol]types.RawPrice, error) {
+				return expectedPrices, nil
+			}, zerolog.New(io.Discard))
+		defer ts.Close()
+
+		var gotPrices map[types.Symbol]types.RawPrice
+		select {
+		case gotPrices = <-ts.PriceUpdates():
+		case <-time.After(100 * time.Millisecond):
+			t.Fatal("timeout when receiving prices")
+		}
+
+		require.Equal(t, 250_000.56, gotPrices["tBTCUSD"].Price)
+		require.Equal(t, 1_234.5, gotPrices["tBTCUSD"].Volume)
+		require.True(t, time.Since(gotPrices["tBTCUSD"].UpdateTime) < 100*time.Millisecond)
+		require.Equal(t, updateTime, gotPrices["tETHUSD"].UpdateTime)
+	})
+
 	t.Run("price update dropped due to shutdown", func(t *testing.T) {
 		// basically every log written ends up here
 		logs := new(bytes.Buffer)
@@ -96,15 +122,16 @@ func TestTickSource(t *testing.T) {
 		ts := NewTickSource(set.New[types.Symbol]("tBTCUSDT"), func(symbols set.Set[types.Symbol], logger zerolog.Logger) (map[types.Symbol]float64, error) {
 			return nil, fmt.Errorf("sentinel error")
 		}, zerolog.New(mw))
-		defer ts.Close()
 
 		<-time.After(UpdateTick + 50*time.Millisecond) // wait for a tick update
 
 		select {
-		case <-ts.PriceUpdates():
-			t.Fatal("no price updates expected")
-		default:
+		case gotPrices := <-ts.PriceUpdates():
+			require.Empty(t, gotPrices, "failed fetches are empty updates")
+		case <-time.After(100 * time.Millisecond):
+			t.Fatal("timeout when receiving the failed fetch")
 		}
+		ts.Close() // stop logging before reading the logs
 
 		require.Contains(t, logs.String(), "sentinel error") // assert an error was reported
 	})
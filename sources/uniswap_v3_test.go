@@ -101,6 +101,9 @@ func createMockEthereumServer() *httptest.Server {
 		response.ID = req.ID
 
 		switch req.Method {
+		case "eth_chainId":
+			response.Result = "0x1"
+
 		case "eth_call":
 			// Extract the 'to' address and 'data' from params
 			if len(req.Params) > 0 {
@@ -198,9 +201,12 @@ func TestUniswapV3PriceUpdate_WithHTTPMock_NoPoolFound(t *testing.T) {
 		_ = json.NewDecoder(r.Body).Decode(&req)
 
 		response := JSONRPCResponse{ID: req.ID}
-		if req.Method == "eth_call" {
+		switch req.Method {
+		case "eth_chainId":
+			response.Result = "0x1"
+		case "eth_call":
 			response.Result = "0x0000000000000000000000000000000000000000000000000000000000000000"
-		} else {
+		default:
 			response.Error = &JSONRPCError{Code: -32601, Message: "Method not found"}
 		}
 
@@ -254,7 +260,8 @@ func TestUniswapV3PriceUpdate_WithHTTPMock_ServerUnavailable(t *testing.T) {
 	_, err := UniswapV3PriceUpdate(symbols, logger)
 	require.Error(t, err)
 
-	errorContainsExpected := strings.Contains(err.Error(), "failed to connect to Ethereum client") ||
+	// the chain id check is the first request sent to the endpoint
+	errorContainsExpected := strings.Contains(err.Error(), "failed to connect to Ethereum") ||
 		strings.Contains(err.Error(), "no pools found") ||
 		strings.Contains(err.Error(), "failed to find pool")
 	assert.True(t, errorContainsExpected, "Should get connection or RPC-related error, got: %v", err)
@@ -281,6 +288,9 @@ func TestUniswapV3PriceUpdate_WithHTTPMock_MultiplePools(t *testing.T) {
 		var response JSONRPCResponse
 		response.ID = req.ID
 
+		if req.Method == "eth_chainId" {
+			response.Result = "0x1"
+		}
 		if req.Method == "eth_call" && len(req.Params) > 0 {
 			to, data, err := parseEthCallParams(req)
 			if err != nil {
@@ -712,6 +722,9 @@ func TestUniswapV3PriceUpdate_PoolSelection_DifferentLiquidities(t *testing.T) {
 		var response JSONRPCResponse
 		response.ID = req.ID
 
+		if req.Method == "eth_chainId" {
+			response.Result = "0x1"
+		}
 		if req.Method == "eth_call" && len(req.Params) > 0 {
 			to, data, err := parseEthCallParams(req)
 			if err != nil {
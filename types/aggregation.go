@@ -0,0 +1,179 @@
+package types
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/NibiruChain/nibiru/v2/x/common/asset"
+	"github.com/NibiruChain/nibiru/v2/x/common/denoms"
+)
+
+// PriceHistorySize is the number of prices kept per symbol of each source. At
+// the default update tick of 8 seconds it covers a bit more than half an hour
+// of prices, which bounds the TWAP window.
+const PriceHistorySize = 256
+
+// AggregationStrategy defines how the aggregate price provider combines the
+// prices reported by multiple sources for the same asset pair.
+type AggregationStrategy string
+
+const (
+	// AggregationMedian queries every source for the pair and votes the median
+	// of the valid prices, so a single misbehaving source cannot move the vote.
+	AggregationMedian AggregationStrategy = "median"
+
+	// AggregationVWAP votes the average of the valid prices weighted by the
+	// 24h volume reported by each source, so deep markets outweigh thin ones.
+	// Falls back to the median if any of the sources does not report volume.
+	AggregationVWAP AggregationStrategy = "vwap"
+
+	// AggregationFirstValid votes the first valid price found while iterating
+	// over the sources in tier order, or by name for pairs without tiers.
+	// Kept as a legacy option.
+	AggregationFirstValid AggregationStrategy = "first_valid"
+)
+
+// Validate returns an error if the strategy is not a known [AggregationStrategy].
+func (s AggregationStrategy) Validate() error {
+	switch s {
+	case AggregationMedian, AggregationVWAP, AggregationFirstValid:
+		return nil
+	default:
+		return fmt.Errorf("unknown aggregation strategy: %q", s)
+	}
+}
+
+// AggregationConfig holds the settings used by the aggregate price provider to
+// combine per-source prices into the price we vote.
+type AggregationConfig struct {
+	// Strategy is the method used to combine the prices of multiple sources.
+	Strategy AggregationStrategy
+	// OutlierMaxStdDev rejects source prices that are more than this many
+	// standard deviations away from the cross-source median. Zero disables it.
+	OutlierMaxStdDev float64
+	// OutlierMaxDeviationPct rejects source prices that are more than this
+	// percentage away from the cross-source median. Zero disables it.
+	OutlierMaxDeviationPct float64
+	// MinSources is the minimum number of independent sources that must
+	// produce a fresh price for a pair before it is voted. Pairs that are not
+	// present default to a single source.
+	MinSources map[asset.Pair]int
+	// TWAPWindow makes every source report the time-weighted average of its
+	// prices over this window instead of its last price. Zero disables it.
+	// It must be covered by the [PriceHistorySize] prices kept per symbol.
+	TWAPWindow time.Duration
+	// DerivedPairs defines the synthetic pairs priced from other pairs.
+	DerivedPairs map[asset.Pair]DerivedPair
+	// SourceTiers lists, per pair, the names of the sources to use in
+	// priority order. Lower tiers are only used while the higher ones do not
+	// reach the quorum of the pair. Pairs that are not present use every
+	// source in a single tier.
+	SourceTiers map[asset.Pair][][]string
+	// PriceBounds limits the source prices of pairs to a range, before
+	// aggregation.
+	PriceBounds map[asset.Pair]PriceBound
+	// Stablecoins are the denoms of USD stablecoins used as quote assets by
+	// sources, e.g. uusdt for BTC_USDT. Prices quoted in a stablecoin are
+	// converted to USD with the live stablecoin price.
+	Stablecoins []string
+	// StablecoinDepegThresholdPct rejects conversions through a stablecoin
+	// whose USD price is more than this percentage away from 1. Zero
+	// disables the check.
+	StablecoinDepegThresholdPct float64
+	// CircuitBreaker excludes misbehaving sources from aggregation for a while.
+	CircuitBreaker CircuitBreakerConfig
+}
+
+// DefaultAggregationConfig returns the [AggregationConfig] used when nothing
+// is configured.
+func DefaultAggregationConfig() AggregationConfig {
+	return AggregationConfig{
+		Strategy:                    AggregationMedian,
+		DerivedPairs:                DefaultDerivedPairs(),
+		PriceBounds:                 DefaultPriceBounds(),
+		Stablecoins:                 []string{denoms.USDT, denoms.USDC},
+		StablecoinDepegThresholdPct: 2,
+		CircuitBreaker:              DefaultCircuitBreakerConfig(),
+	}
+}
+
+// Validate returns an error if the [AggregationConfig] is invalid.
+func (c AggregationConfig) Validate() error {
+	if err := c.Strategy.Validate(); err != nil {
+		return err
+	}
+	if c.OutlierMaxStdDev < 0 {
+		return fmt.Errorf("outlier max standard deviations must not be negative: %f", c.OutlierMaxStdDev)
+	}
+	if c.OutlierMaxDeviationPct < 0 {
+		return fmt.Errorf("outlier max deviation percentage must not be negative: %f", c.OutlierMaxDeviationPct)
+	}
+	if c.StablecoinDepegThresholdPct < 0 {
+		return fmt.Errorf("stablecoin de-peg threshold must not be negative: %f", c.StablecoinDepegThresholdPct)
+	}
+	if c.TWAPWindow < 0 {
+		return fmt.Errorf("TWAP window must not be negative: %s", c.TWAPWindow)
+	}
+	if err := c.CircuitBreaker.Validate(); err != nil {
+		return err
+	}
+	for pair, minSources := range c.MinSources {
+		if minSources < 1 {
+			return fmt.Errorf("min sources for %s must be at least 1: %d", pair, minSources)
+		}
+	}
+	for pair, tiers := range c.SourceTiers {
+		if err := validateSourceTiers(tiers); err != nil {
+			return fmt.Errorf("invalid source tiers for %s: %w", pair, err)
+		}
+	}
+	for pair, bound := range c.PriceBounds {
+		if err := pair.Validate(); err != nil {
+			return fmt.Errorf("invalid price bound %s: %w", pair, err)
+		}
+		if err := bound.Validate(); err != nil {
+			return fmt.Errorf("invalid price bound %s: %w", pair, err)
+		}
+	}
+	return ValidateDerivedPairs(c.DerivedPairs)
+}
+
+// validateSourceTiers returns an error if a tier is empty or a source is
+// listed more than once.
+func validateSourceTiers(tiers [][]string) error {
+	if len(tiers) == 0 {
+		return fmt.Errorf("no source tiers")
+	}
+	seen := map[string]bool{}
+	for i, tier := range tiers {
+		if len(tier) == 0 {
+			return fmt.Errorf("source tier %d is empty", i+1)
+		}
+		for _, sourceName := range tier {
+			if seen[sourceName] {
+				return fmt.Errorf("source %s is listed more than once", sourceName)
+			}
+			seen[sourceName] = true
+		}
+	}
+	return nil
+}
+
+// IsStablecoin reports whether the denom is a configured USD stablecoin.
+func (c AggregationConfig) IsStablecoin(denom string) bool {
+	for _, stablecoin := range c.Stablecoins {
+		if stablecoin == denom {
+			return true
+		}
+	}
+	return false
+}
+
+// MinSourcesFor returns the minimum number of sources required to vote a
+// price for the given pair.
+func (c AggregationConfig) MinSourcesFor(pair asset.Pair) int {
+	if minSources, ok := c.MinSources[pair]; ok {
+		return minSources
+	}
+	return 1
+}
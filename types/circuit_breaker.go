@@ -0,0 +1,42 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// CircuitBreakerConfig holds the settings of the circuit breaker wrapped
+// around each source, which excludes a misbehaving source from aggregation
+// for a while.
+type CircuitBreakerConfig struct {
+	// MaxFailures opens the breaker after this many consecutive failed
+	// fetches of the source. Zero disables it.
+	MaxFailures int
+	// MaxOutliers opens the breaker after this many consecutive fetches whose
+	// price of one of the pairs is rejected as an outlier. Zero disables it.
+	MaxOutliers int
+	// Cooldown is how long the source is excluded before being probed again.
+	Cooldown time.Duration
+}
+
+// DefaultCircuitBreakerConfig returns the [CircuitBreakerConfig] used when
+// nothing is configured: the breaker is disabled.
+func DefaultCircuitBreakerConfig() CircuitBreakerConfig {
+	return CircuitBreakerConfig{Cooldown: time.Minute}
+}
+
+// Enabled reports whether the breaker can open.
+func (c CircuitBreakerConfig) Enabled() bool {
+	return c.MaxFailures > 0 || c.MaxOutliers > 0
+}
+
+// Validate returns an error if the [CircuitBreakerConfig] is invalid.
+func (c CircuitBreakerConfig) Validate() error {
+	if c.MaxFailures < 0 || c.MaxOutliers < 0 {
+		return fmt.Errorf("circuit breaker thresholds must not be negative: %d failures, %d outliers", c.MaxFailures, c.MaxOutliers)
+	}
+	if c.Enabled() && c.Cooldown <= 0 {
+		return fmt.Errorf("circuit breaker cooldown must be positive: %s", c.Cooldown)
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package types
+
+import "fmt"
+
+// ConsensusDeviationConfig holds the settings of the check of our prices
+// against the last exchange rates the oracle came to consensus on.
+type ConsensusDeviationConfig struct {
+	// MaxDeviationPct is the maximum deviation, in percent, of a price from
+	// the last consensus exchange rate of its pair. Zero disables the check.
+	MaxDeviationPct float64
+	// Abstain switches deviating pairs to abstain. Otherwise they are only
+	// logged and counted.
+	Abstain bool
+}
+
+// Validate returns an error if the [ConsensusDeviationConfig] is invalid.
+func (c ConsensusDeviationConfig) Validate() error {
+	if c.MaxDeviationPct < 0 {
+		return fmt.Errorf("max consensus deviation percentage must not be negative: %f", c.MaxDeviationPct)
+	}
+	return nil
+}
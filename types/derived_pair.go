@@ -0,0 +1,126 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/NibiruChain/nibiru/v2/x/common/asset"
+)
+
+// DerivedPairOperation is the arithmetic operation used to compute the price
+// of a [DerivedPair] from the prices of its operand pairs.
+type DerivedPairOperation string
+
+const (
+	// DerivedPairMultiply prices the pair as the product of its operands,
+	// e.g. ustnibi:uusd = ustnibi:unibi * unibi:uusd.
+	DerivedPairMultiply DerivedPairOperation = "multiply"
+
+	// DerivedPairDivide prices the pair as the quotient of its two operands,
+	// e.g. ueth:ubtc = ueth:uusd / ubtc:uusd.
+	DerivedPairDivide DerivedPairOperation = "divide"
+)
+
+// DerivedPair defines a synthetic pair whose price is computed from the prices
+// of other pairs instead of being fetched from a source.
+type DerivedPair struct {
+	// Operation is the arithmetic operation applied to the operand prices.
+	Operation DerivedPairOperation `json:"operation"`
+	// Pairs are the operands, in order. Operands may themselves be derived.
+	Pairs []asset.Pair `json:"pairs"`
+}
+
+// Validate returns an error if the [DerivedPair] is malformed.
+func (d DerivedPair) Validate() error {
+	switch d.Operation {
+	case DerivedPairMultiply:
+		if len(d.Pairs) < 2 {
+			return fmt.Errorf("%s needs at least 2 pairs, got %d", d.Operation, len(d.Pairs))
+		}
+	case DerivedPairDivide:
+		if len(d.Pairs) != 2 {
+			return fmt.Errorf("%s needs exactly 2 pairs, got %d", d.Operation, len(d.Pairs))
+		}
+	default:
+		return fmt.Errorf("unknown derived pair operation: %q", d.Operation)
+	}
+	for _, pair := range d.Pairs {
+		if err := pair.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DefaultDerivedPairs returns the derived pairs supported out of the box.
+func DefaultDerivedPairs() map[asset.Pair]DerivedPair {
+	return map[asset.Pair]DerivedPair{
+		"ustnibi:uusd": {
+			Operation: DerivedPairMultiply,
+			Pairs:     []asset.Pair{"ustnibi:unibi", "unibi:uusd"},
+		},
+		// USDa is only quoted against USDT, on Uniswap V3.
+		"usda:usd": {
+			Operation: DerivedPairMultiply,
+			Pairs:     []asset.Pair{"usda:uusdt", "uusdt:uusd"},
+		},
+		"susda:usd": {
+			Operation: DerivedPairMultiply,
+			Pairs:     []asset.Pair{"susda:usda", "usda:usd"},
+		},
+	}
+}
+
+// ValidateDerivedPairs validates every [DerivedPair] and returns an error if a
+// derived pair depends on itself, directly or through other derived pairs.
+func ValidateDerivedPairs(derivedPairs map[asset.Pair]DerivedPair) error {
+	// iterate in a deterministic order so errors are reproducible
+	pairs := make([]asset.Pair, 0, len(derivedPairs))
+	for pair, derivedPair := range derivedPairs {
+		if err := pair.Validate(); err != nil {
+			return fmt.Errorf("invalid derived pair %s: %w", pair, err)
+		}
+		if err := derivedPair.Validate(); err != nil {
+			return fmt.Errorf("invalid derived pair %s: %w", pair, err)
+		}
+		pairs = append(pairs, pair)
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i] < pairs[j] })
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[asset.Pair]int, len(derivedPairs))
+
+	var visit func(pair asset.Pair, path []asset.Pair) error
+	visit = func(pair asset.Pair, path []asset.Pair) error {
+		derivedPair, isDerived := derivedPairs[pair]
+		if !isDerived {
+			return nil
+		}
+		switch state[pair] {
+		case visiting:
+			return fmt.Errorf("derived pairs cycle detected: %v", append(path, pair))
+		case visited:
+			return nil
+		}
+
+		state[pair] = visiting
+		for _, operand := range derivedPair.Pairs {
+			if err := visit(operand, append(path, pair)); err != nil {
+				return err
+			}
+		}
+		state[pair] = visited
+		return nil
+	}
+
+	for _, pair := range pairs {
+		if err := visit(pair, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
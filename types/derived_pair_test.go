@@ -0,0 +1,74 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/NibiruChain/nibiru/v2/x/common/asset"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateDerivedPairs(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		derivedPairs map[asset.Pair]DerivedPair
+		wantErr      string
+	}{
+		{
+			name:         "defaults",
+			derivedPairs: DefaultDerivedPairs(),
+		},
+		{
+			name: "nested derived pairs",
+			derivedPairs: map[asset.Pair]DerivedPair{
+				"ustnibi:uusd": {Operation: DerivedPairMultiply, Pairs: []asset.Pair{"ustnibi:unibi", "unibi:uusd"}},
+				"ustnibi:ueur": {Operation: DerivedPairDivide, Pairs: []asset.Pair{"ustnibi:uusd", "ueur:uusd"}},
+			},
+		},
+		{
+			name: "unknown operation",
+			derivedPairs: map[asset.Pair]DerivedPair{
+				"ustnibi:uusd": {Operation: "add", Pairs: []asset.Pair{"ustnibi:unibi", "unibi:uusd"}},
+			},
+			wantErr: "unknown derived pair operation",
+		},
+		{
+			name: "divide needs two pairs",
+			derivedPairs: map[asset.Pair]DerivedPair{
+				"ueth:ubtc": {Operation: DerivedPairDivide, Pairs: []asset.Pair{"ueth:uusd", "ubtc:uusd", "uusd:uusdt"}},
+			},
+			wantErr: "exactly 2 pairs",
+		},
+		{
+			name: "invalid operand",
+			derivedPairs: map[asset.Pair]DerivedPair{
+				"ustnibi:uusd": {Operation: DerivedPairMultiply, Pairs: []asset.Pair{"ustnibi", "unibi:uusd"}},
+			},
+			wantErr: "invalid derived pair",
+		},
+		{
+			name: "self reference",
+			derivedPairs: map[asset.Pair]DerivedPair{
+				"ustnibi:uusd": {Operation: DerivedPairMultiply, Pairs: []asset.Pair{"ustnibi:uusd", "unibi:uusd"}},
+			},
+			wantErr: "cycle detected",
+		},
+		{
+			name: "indirect cycle",
+			derivedPairs: map[asset.Pair]DerivedPair{
+				"uaaa:uusd": {Operation: DerivedPairMultiply, Pairs: []asset.Pair{"uaaa:ubbb", "ubbb:uusd"}},
+				"ubbb:uusd": {Operation: DerivedPairMultiply, Pairs: []asset.Pair{"ubbb:uccc", "uccc:uusd"}},
+				"uccc:uusd": {Operation: DerivedPairDivide, Pairs: []asset.Pair{"uccc:uaaa", "uaaa:uusd"}},
+			},
+			wantErr: "cycle detected",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateDerivedPairs(tc.derivedPairs)
+			if tc.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
@@ -14,16 +14,35 @@ import (
 
 // NetworkConfig holds configuration for an EVM network
 type NetworkConfig struct {
-	Name               string
-	DefaultEndpoints   []string
-	EnvEndpoint        string // Environment variable for single endpoint
-	EnvPublicEndpoints string // Environment variable for multiple endpoints
+	Name               string   `json:"-"`
+	ChainID            uint64   `json:"chain_id"`      // Expected chain ID, checked on connect if set
+	DefaultEndpoints   []string `json:"rpc_endpoints"` // Endpoints tried in order
+	Timeout            Duration `json:"timeout"`       // Timeout of each endpoint, overriding the caller's if set
+	EnvEndpoint        string   `json:"-"`             // Environment variable for single endpoint
+	EnvPublicEndpoints string   `json:"-"`             // Environment variable for multiple endpoints
+}
+
+// Validate returns an error if the [NetworkConfig] is invalid.
+func (c NetworkConfig) Validate() error {
+	if len(c.DefaultEndpoints) == 0 {
+		return fmt.Errorf("no rpc endpoints")
+	}
+	for _, endpoint := range c.DefaultEndpoints {
+		if strings.TrimSpace(endpoint) == "" {
+			return fmt.Errorf("empty rpc endpoint")
+		}
+	}
+	if c.Timeout < 0 {
+		return fmt.Errorf("timeout must not be negative: %s", time.Duration(c.Timeout))
+	}
+	return nil
 }
 
 // DefaultNetworkConfigs provides configurations for supported networks
 var DefaultNetworkConfigs = map[string]NetworkConfig{
 	"ethereum": {
-		Name: "ethereum",
+		Name:    "ethereum",
+		ChainID: 1,
 		DefaultEndpoints: []string{
 			"https://eth.llamarpc.com",
 			"https://eth-mainnet.public.blastapi.io",
@@ -35,7 +54,8 @@ var DefaultNetworkConfigs = map[string]NetworkConfig{
 		EnvPublicEndpoints: "ETHEREUM_RPC_PUBLIC_ENDPOINTS",
 	},
 	"b2": {
-		Name: "b2",
+		Name:    "b2",
+		ChainID: 223,
 		DefaultEndpoints: []string{
 			"https://rpc.bsquared.network",
 			"https://mainnet.b2-rpc.com",
@@ -53,11 +73,51 @@ var (
 	rpcMutex        sync.RWMutex
 )
 
+// Global variables holding the networks registered with RegisterNetworks
+var (
+	registeredNetworks = make(map[string]NetworkConfig)
+	networksMutex      sync.RWMutex
+)
+
+// RegisterNetworks adds EVM networks to the supported ones, keyed by name.
+// A network named as one of DefaultNetworkConfigs replaces it.
+func RegisterNetworks(networks map[string]NetworkConfig) error {
+	for name, config := range networks {
+		if err := config.Validate(); err != nil {
+			return fmt.Errorf("invalid network %s: %w", name, err)
+		}
+	}
+
+	networksMutex.Lock()
+	defer networksMutex.Unlock()
+	for name, config := range networks {
+		config.Name = name
+		registeredNetworks[name] = config
+	}
+	return nil
+}
+
+// GetNetworkConfig returns the configuration of a registered or default network
+func GetNetworkConfig(networkName string) (NetworkConfig, error) {
+	networksMutex.RLock()
+	config, exists := registeredNetworks[networkName]
+	networksMutex.RUnlock()
+	if exists {
+		return config, nil
+	}
+
+	config, exists = DefaultNetworkConfigs[networkName]
+	if !exists {
+		return NetworkConfig{}, fmt.Errorf("unsupported network: %s", networkName)
+	}
+	return config, nil
+}
+
 // GetRPCEndpoints returns the list of RPC endpoints to try for a given network
 func GetRPCEndpoints(networkName string) ([]string, error) {
-	config, exists := DefaultNetworkConfigs[networkName]
-	if !exists {
-		return nil, fmt.Errorf("unsupported network: %s", networkName)
+	config, err := GetNetworkConfig(networkName)
+	if err != nil {
+		return nil, err
 	}
 
 	// Check if priority endpoint is set
@@ -71,7 +131,7 @@ func GetRPCEndpoints(networkName string) ([]string, error) {
 	if publicEndpoints != "" {
 		endpoints = strings.Split(publicEndpoints, ",")
 	} else {
-		endpoints = config.DefaultEndpoints
+		endpoints = append([]string(nil), config.DefaultEndpoints...)
 	}
 
 	// Trim whitespace from each endpoint
@@ -124,12 +184,21 @@ func TryRPCEndpoint(networkName, endpoint string, timeout time.Duration, logger
 		}
 
 		// Test the connection by getting the latest block number
-		_, err = client.BlockNumber(context.Background())
+		_, err = client.BlockNumber(ctx)
 		if err != nil {
 			client.Close()
 			resultCh <- result{nil, err}
 			return
 		}
+
+		// Check that the endpoint serves the expected chain
+		if config, err := GetNetworkConfig(networkName); err == nil {
+			if err := verifyChainID(ctx, client, config.ChainID); err != nil {
+				client.Close()
+				resultCh <- result{nil, err}
+				return
+			}
+		}
 		resultCh <- result{client, nil}
 	}()
 
@@ -153,11 +222,15 @@ func TryRPCEndpoint(networkName, endpoint string, timeout time.Duration, logger
 	}
 }
 
-// ConnectToNetwork creates a connection to the specified EVM network
+// ConnectToNetwork creates a connection to the specified EVM network. The
+// timeout of each endpoint is the one of the network if set.
 func ConnectToNetwork(networkName string, timeout time.Duration, logger zerolog.Logger) (*ethclient.Client, error) {
-	config, exists := DefaultNetworkConfigs[networkName]
-	if !exists {
-		return nil, fmt.Errorf("unsupported network: %s", networkName)
+	config, err := GetNetworkConfig(networkName)
+	if err != nil {
+		return nil, err
+	}
+	if config.Timeout > 0 {
+		timeout = time.Duration(config.Timeout)
 	}
 
 	endpoints, err := GetRPCEndpoints(networkName)
@@ -174,6 +247,12 @@ func ConnectToNetwork(networkName string, timeout time.Duration, logger zerolog.
 		if connErr != nil {
 			return nil, fmt.Errorf("failed to connect to %s client: %w", networkName, connErr)
 		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		if err := verifyChainID(ctx, client, config.ChainID); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("failed to connect to %s client: %w", networkName, err)
+		}
 		return client, nil
 	}
 
@@ -193,6 +272,22 @@ func ConnectToNetwork(networkName string, timeout time.Duration, logger zerolog.
 	return nil, fmt.Errorf("failed to connect to any %s RPC endpoint. Last error: %w", networkName, connErr)
 }
 
+// verifyChainID returns an error if the client is connected to another chain
+// than the expected one. A zero expected chain ID is not checked.
+func verifyChainID(ctx context.Context, client *ethclient.Client, expected uint64) error {
+	if expected == 0 {
+		return nil
+	}
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get chain id: %w", err)
+	}
+	if !chainID.IsUint64() || chainID.Uint64() != expected {
+		return fmt.Errorf("unexpected chain id %s, expected %d", chainID, expected)
+	}
+	return nil
+}
+
 func TryEthereumRPCEndpoint(endpoint string, timeout time.Duration, logger zerolog.Logger) (*ethclient.Client, error) {
 	return TryRPCEndpoint("ethereum", endpoint, timeout, logger)
 }
@@ -2,6 +2,11 @@ package types
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -57,3 +62,93 @@ func TestGetRPCEndpoints_UnsupportedNetwork(t *testing.T) {
 	assert.Nil(t, endpoints)
 	assert.Contains(t, err.Error(), "unsupported network")
 }
+
+// newTestRPCServer returns a JSON-RPC server answering eth_blockNumber and
+// eth_chainId with the given chain ID.
+func newTestRPCServer(t *testing.T, chainID uint64) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		result := "0x10"
+		if req.Method == "eth_chainId" {
+			result = fmt.Sprintf("0x%x", chainID)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"jsonrpc": "2.0", "id": %s, "result": %q}`, req.ID, result)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRegisterNetworks(t *testing.T) {
+	logger := zerolog.New(io.Discard)
+	arbitrum := newTestRPCServer(t, 42161)
+	wrongChain := newTestRPCServer(t, 1)
+
+	err := RegisterNetworks(map[string]NetworkConfig{
+		"test-arbitrum": {
+			ChainID:          42161,
+			DefaultEndpoints: []string{wrongChain.URL, arbitrum.URL},
+			Timeout:          Duration(time.Second),
+		},
+		"test-wrong-chain": {
+			ChainID:          42161,
+			DefaultEndpoints: []string{wrongChain.URL},
+		},
+	})
+	require.NoError(t, err)
+
+	endpoints, err := GetRPCEndpoints("test-arbitrum")
+	require.NoError(t, err)
+	assert.Equal(t, []string{wrongChain.URL, arbitrum.URL}, endpoints)
+
+	client, err := ConnectToNetwork("test-arbitrum", 10*time.Second, logger)
+	require.NoError(t, err)
+	client.Close()
+
+	// the last working endpoint is tried first
+	endpoints, err = GetRPCEndpoints("test-arbitrum")
+	require.NoError(t, err)
+	assert.Equal(t, []string{arbitrum.URL, wrongChain.URL}, endpoints)
+
+	_, err = ConnectToNetwork("test-wrong-chain", 10*time.Second, logger)
+	require.ErrorContains(t, err, "unexpected chain id 1, expected 42161")
+
+	// the default networks are still supported
+	_, err = GetRPCEndpoints("ethereum")
+	require.NoError(t, err)
+}
+
+func TestRegisterNetworks_Invalid(t *testing.T) {
+	for name, tc := range map[string]struct {
+		config  NetworkConfig
+		wantErr string
+	}{
+		"no endpoints": {
+			config:  NetworkConfig{ChainID: 8453},
+			wantErr: "invalid network base: no rpc endpoints",
+		},
+		"empty endpoint": {
+			config:  NetworkConfig{DefaultEndpoints: []string{" "}},
+			wantErr: "invalid network base: empty rpc endpoint",
+		},
+		"negative timeout": {
+			config:  NetworkConfig{DefaultEndpoints: []string{"https://mainnet.base.org"}, Timeout: Duration(-time.Second)},
+			wantErr: "invalid network base: timeout must not be negative",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := RegisterNetworks(map[string]NetworkConfig{"base": tc.config})
+			require.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+
+	_, err := GetRPCEndpoints("base")
+	require.ErrorContains(t, err, "unsupported network: base")
+}
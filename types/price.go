@@ -17,9 +17,13 @@ const (
 	PriceAbstain float64 = -1
 )
 
+// RawPrice defines the price of a symbol as reported by a [Source].
 type RawPrice struct {
 	Price      float64
 	UpdateTime time.Time
+	// Volume is the 24h traded volume in the base asset reported along with
+	// the price, or zero if the source does not report volume.
+	Volume float64
 }
 
 // Price defines the price of a symbol.
@@ -29,7 +33,19 @@ type Price struct {
 	// Price defines the symbol's price.
 	Price float64
 	// SourceName defines the source which is providing the prices.
+	// When the price is aggregated from several sources, it is the
+	// comma-separated list of Sources. For derived pairs, it is the
+	// source name of the first pair the price is derived from.
 	SourceName string
+	// Sources lists the names of the sources that contributed to the price.
+	Sources []string
+	// Depth is the number of sources that priced the pair's own leg, the
+	// first pair of its route, not counting the sources converting its quote
+	// asset. For derived pairs, it is the smallest depth of their operands.
+	Depth int
+	// Volume is the 24h traded volume in the base asset reported by the
+	// source, or zero if unknown.
+	Volume float64
 	// Valid reports whether the price is valid or not.
 	// If not valid then an abstain vote will be posted.
 	// Computed from the update time.
@@ -42,3 +58,8 @@ type Price struct {
 // If there's a failure in updating only one price then the map can be returned
 // without the provided symbol.
 type FetchPricesFunc func(symbols set.Set[Symbol], logger zerolog.Logger) (map[Symbol]float64, error)
+
+// FetchRawPricesFunc is like [FetchPricesFunc] but returns a [RawPrice] per
+// symbol, for sources that report more than the price, such as traded volume.
+// A zero UpdateTime is replaced by the time the prices were fetched.
+type FetchRawPricesFunc func(symbols set.Set[Symbol], logger zerolog.Logger) (map[Symbol]RawPrice, error)
@@ -0,0 +1,77 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/NibiruChain/nibiru/v2/x/common/asset"
+)
+
+// PriceBoundAction is what happens to a source price outside of its [PriceBound].
+type PriceBoundAction string
+
+const (
+	// PriceBoundClamp replaces the price with the bound it crossed.
+	PriceBoundClamp PriceBoundAction = "clamp"
+
+	// PriceBoundAbstain discards the price, so the source does not contribute
+	// to the vote.
+	PriceBoundAbstain PriceBoundAction = "abstain"
+
+	// PriceBoundAlert keeps the price as is, only logging and counting the
+	// violation.
+	PriceBoundAlert PriceBoundAction = "alert"
+)
+
+// PriceBound defines the range of acceptable source prices for a pair.
+type PriceBound struct {
+	// Min is the floor of the price. Zero means no floor.
+	Min float64 `json:"min"`
+	// Max is the ceiling of the price. Zero means no ceiling.
+	Max float64 `json:"max"`
+	// Action is applied to prices outside of [Min, Max].
+	Action PriceBoundAction `json:"action"`
+}
+
+// Validate returns an error if the [PriceBound] is malformed.
+func (b PriceBound) Validate() error {
+	switch b.Action {
+	case PriceBoundClamp, PriceBoundAbstain, PriceBoundAlert:
+	default:
+		return fmt.Errorf("unknown price bound action: %q", b.Action)
+	}
+	if b.Min < 0 || b.Max < 0 {
+		return fmt.Errorf("price bounds must not be negative: [%f, %f]", b.Min, b.Max)
+	}
+	if b.Min == 0 && b.Max == 0 {
+		return fmt.Errorf("price bound needs a min or a max")
+	}
+	if b.Max > 0 && b.Min > b.Max {
+		return fmt.Errorf("price bound min %f is greater than max %f", b.Min, b.Max)
+	}
+	return nil
+}
+
+// Violated reports whether the price is outside of the bound.
+func (b PriceBound) Violated(price float64) bool {
+	return price < b.Min || (b.Max > 0 && price > b.Max)
+}
+
+// Clamp returns the price limited to the bound.
+func (b PriceBound) Clamp(price float64) float64 {
+	if price < b.Min {
+		return b.Min
+	}
+	if b.Max > 0 && price > b.Max {
+		return b.Max
+	}
+	return price
+}
+
+// DefaultPriceBounds returns the price bounds applied out of the box.
+func DefaultPriceBounds() map[asset.Pair]PriceBound {
+	return map[asset.Pair]PriceBound{
+		// USDa is backed by USDT and only traded on Uniswap, whose pools are
+		// cheap to manipulate.
+		"usda:uusdt": {Min: 0.95, Max: 1.01, Action: PriceBoundClamp},
+	}
+}
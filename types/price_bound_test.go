@@ -0,0 +1,69 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceBound(t *testing.T) {
+	testCases := []struct {
+		name     string
+		bound    PriceBound
+		price    float64
+		violated bool
+		clamped  float64
+	}{
+		{
+			name:    "inside",
+			bound:   PriceBound{Min: 0.95, Max: 1.01, Action: PriceBoundClamp},
+			price:   1,
+			clamped: 1,
+		},
+		{
+			name:     "below floor",
+			bound:    PriceBound{Min: 0.95, Max: 1.01, Action: PriceBoundClamp},
+			price:    0.5,
+			violated: true,
+			clamped:  0.95,
+		},
+		{
+			name:     "above ceiling",
+			bound:    PriceBound{Min: 0.95, Max: 1.01, Action: PriceBoundClamp},
+			price:    2,
+			violated: true,
+			clamped:  1.01,
+		},
+		{
+			name:    "no ceiling",
+			bound:   PriceBound{Min: 0.95, Action: PriceBoundClamp},
+			price:   1_000,
+			clamped: 1_000,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.NoError(t, tc.bound.Validate())
+			require.Equal(t, tc.violated, tc.bound.Violated(tc.price))
+			require.Equal(t, tc.clamped, tc.bound.Clamp(tc.price))
+		})
+	}
+}
+
+func TestPriceBoundValidate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		bound   PriceBound
+		wantErr string
+	}{
+		{"unknown action", PriceBound{Min: 1, Action: "drop"}, "unknown price bound action"},
+		{"negative", PriceBound{Min: -1, Action: PriceBoundAlert}, "must not be negative"},
+		{"unbounded", PriceBound{Action: PriceBoundAlert}, "needs a min or a max"},
+		{"min above max", PriceBound{Min: 2, Max: 1, Action: PriceBoundAbstain}, "greater than max"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.ErrorContains(t, tc.bound.Validate(), tc.wantErr)
+		})
+	}
+}
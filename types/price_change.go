@@ -0,0 +1,53 @@
+package types
+
+import "fmt"
+
+// PriceChangeAction is what happens to a price that moved more than allowed
+// since the previous voting period.
+type PriceChangeAction string
+
+const (
+	// PriceChangeAbstain abstains the pair for the voting period.
+	PriceChangeAbstain PriceChangeAction = "abstain"
+
+	// PriceChangeConfirm votes the price only if the pair is quoted by at
+	// least two sources, and abstains otherwise.
+	PriceChangeConfirm PriceChangeAction = "confirm"
+)
+
+// PriceChangeConfig holds the settings of the guard against prices moving
+// too much between two voting periods.
+type PriceChangeConfig struct {
+	// MaxChangePct is the maximum change, in percent, of the price of a pair
+	// since the previous voting period. Zero disables the guard.
+	MaxChangePct float64
+	// Action is applied to prices that moved more than MaxChangePct.
+	Action PriceChangeAction
+	// SettlePeriods is the number of consecutive voting periods a price must
+	// stay at its new level, within MaxChangePct, before that level is voted
+	// and becomes the reference of the pair.
+	SettlePeriods int
+}
+
+// DefaultPriceChangeConfig returns the [PriceChangeConfig] used when nothing
+// is configured: the guard is disabled, and a new level is voted after 3
+// voting periods once enabled.
+func DefaultPriceChangeConfig() PriceChangeConfig {
+	return PriceChangeConfig{Action: PriceChangeAbstain, SettlePeriods: 3}
+}
+
+// Validate returns an error if the [PriceChangeConfig] is invalid.
+func (c PriceChangeConfig) Validate() error {
+	if c.MaxChangePct < 0 {
+		return fmt.Errorf("max price change percentage must not be negative: %f", c.MaxChangePct)
+	}
+	if c.SettlePeriods < 1 {
+		return fmt.Errorf("price change settle periods must be at least 1: %d", c.SettlePeriods)
+	}
+	switch c.Action {
+	case PriceChangeAbstain, PriceChangeConfirm:
+		return nil
+	default:
+		return fmt.Errorf("unknown price change action: %q", c.Action)
+	}
+}
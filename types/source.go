@@ -6,8 +6,9 @@ package types
 // symbols.
 type Source interface {
 	// PriceUpdates is a readonly channel which provides
-	// the latest prices update. Updates can be provided
-	// for one asset only or in batches, hence the map.
+	// the latest prices update, one per fetch of the
+	// symbols. Symbols missing from an update failed to
+	// be fetched, and a failed fetch is an empty update.
 	PriceUpdates() <-chan map[Symbol]RawPrice
 	// Close closes the Source.
 	Close()
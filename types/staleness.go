@@ -0,0 +1,83 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/NibiruChain/nibiru/v2/x/common/asset"
+)
+
+// Duration is a [time.Duration] encoded in JSON as a Go duration string,
+// e.g. "30s" or "2m".
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"30s\": %w", err)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// StalenessConfig holds the maximum age of the prices of a source, read from
+// the source's entry in DATASOURCE_CONFIG_MAP next to its own settings:
+//
+//	{"coingecko": {"max_price_age": "2m", "pair_max_price_age": {"ubtc:uusd": "30s"}}}
+type StalenessConfig struct {
+	// MaxPriceAge is the maximum age of the prices of the source. Defaults
+	// to [PriceTimeout], or to a longer age for sources reporting on-chain
+	// update times.
+	MaxPriceAge Duration `json:"max_price_age"`
+	// PairMaxPriceAge overrides MaxPriceAge for specific pairs.
+	PairMaxPriceAge map[asset.Pair]Duration `json:"pair_max_price_age"`
+}
+
+// ParseStalenessConfig reads the [StalenessConfig] from the configuration of
+// a source. Other settings of the source are ignored.
+func ParseStalenessConfig(sourceConfig json.RawMessage) (StalenessConfig, error) {
+	var config StalenessConfig
+	if len(sourceConfig) == 0 {
+		return config, nil
+	}
+	if err := json.Unmarshal(sourceConfig, &config); err != nil {
+		return StalenessConfig{}, err
+	}
+	return config, config.Validate()
+}
+
+// Validate returns an error if the [StalenessConfig] is invalid.
+func (c StalenessConfig) Validate() error {
+	if c.MaxPriceAge < 0 {
+		return fmt.Errorf("max price age must not be negative: %s", time.Duration(c.MaxPriceAge))
+	}
+	for pair, maxPriceAge := range c.PairMaxPriceAge {
+		if err := pair.Validate(); err != nil {
+			return err
+		}
+		if maxPriceAge <= 0 {
+			return fmt.Errorf("max price age for %s must be positive: %s", pair, time.Duration(maxPriceAge))
+		}
+	}
+	return nil
+}
+
+// MaxPriceAgeFor returns the maximum age of a price for the given pair.
+func (c StalenessConfig) MaxPriceAgeFor(pair asset.Pair) time.Duration {
+	if maxPriceAge, ok := c.PairMaxPriceAge[pair]; ok {
+		return time.Duration(maxPriceAge)
+	}
+	if c.MaxPriceAge > 0 {
+		return time.Duration(c.MaxPriceAge)
+	}
+	return PriceTimeout
+}
@@ -0,0 +1,66 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/NibiruChain/nibiru/v2/x/common/asset"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStalenessConfig(t *testing.T) {
+	testCases := []struct {
+		name    string
+		config  string
+		want    map[asset.Pair]time.Duration
+		wantErr string
+	}{
+		{
+			name:   "empty config uses the default",
+			config: ``,
+			want:   map[asset.Pair]time.Duration{"ubtc:uusd": PriceTimeout},
+		},
+		{
+			name:   "other settings are ignored",
+			config: `{"api_key": "secret"}`,
+			want:   map[asset.Pair]time.Duration{"ubtc:uusd": PriceTimeout},
+		},
+		{
+			name:   "source and pair overrides",
+			config: `{"max_price_age": "2m", "pair_max_price_age": {"ubtc:uusd": "5s"}}`,
+			want: map[asset.Pair]time.Duration{
+				"ubtc:uusd": 5 * time.Second,
+				"ueth:uusd": 2 * time.Minute,
+			},
+		},
+		{
+			name:    "invalid duration",
+			config:  `{"max_price_age": "2 minutes"}`,
+			wantErr: "unknown unit",
+		},
+		{
+			name:    "numeric duration",
+			config:  `{"max_price_age": 30}`,
+			wantErr: "duration must be a string",
+		},
+		{
+			name:    "non-positive pair duration",
+			config:  `{"pair_max_price_age": {"ubtc:uusd": "0s"}}`,
+			wantErr: "must be positive",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config, err := ParseStalenessConfig(json.RawMessage(tc.config))
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			for pair, want := range tc.want {
+				require.Equal(t, want, config.MaxPriceAgeFor(pair))
+			}
+		})
+	}
+}